@@ -0,0 +1,166 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+const contentHashPrefix = "sha256:"
+
+// DefaultMaxBytes bounds a single attachment (a phone photo fits easily).
+const DefaultMaxBytes = 20 << 20
+
+var (
+	ErrTooLarge          = errors.New("attachment too large")
+	ErrInvalidMIMEType   = errors.New("invalid mime type")
+	ErrUnknownAttachment = errors.New("unknown attachment")
+	ErrCorruptAttachment = errors.New("attachment content does not match its hash")
+	errNotRegistration   = errors.New("attachment stream does not start with AttachmentRegistered")
+)
+
+// FileStore is the adapter-side attachment registry. Blobs are kept under
+// root, addressed by their SHA-256, and every attachment is registered with
+// an AttachmentRegistered event in its own stream. Attachment IDs derive from
+// the content hash, so putting the same bytes twice returns the same
+// registration.
+type FileStore struct {
+	root     string
+	events   store.EventStore
+	maxBytes int64
+}
+
+func NewFileStore(root string, events store.EventStore) (*FileStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("blob root is required")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{root: root, events: events, maxBytes: DefaultMaxBytes}, nil
+}
+
+// WithMaxBytes replaces DefaultMaxBytes.
+func (s *FileStore) WithMaxBytes(maxBytes int64) *FileStore {
+	s.maxBytes = maxBytes
+	return s
+}
+
+// Put stores content and registers it. An empty mimeType is sniffed from the
+// first bytes of content. metadata (who uploaded it, when) is recorded with
+// the registration; content stored again keeps its first registration.
+func (s *FileStore) Put(ctx context.Context, content io.Reader, mimeType string, metadata store.Metadata) (core.AttachmentRegistered, error) {
+	head := make([]byte, 512)
+	headSize, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return core.AttachmentRegistered{}, err
+	}
+	head = head[:headSize]
+	if mimeType == "" {
+		mimeType = http.DetectContentType(head)
+	}
+	mediaType, params, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return core.AttachmentRegistered{}, fmt.Errorf("%w: %v", ErrInvalidMIMEType, err)
+	}
+	mimeType = mime.FormatMediaType(mediaType, params)
+
+	upload, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return core.AttachmentRegistered{}, err
+	}
+	defer os.Remove(upload.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(upload, hash), io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxBytes+1))
+	if closeErr := upload.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return core.AttachmentRegistered{}, err
+	}
+	if size > s.maxBytes {
+		return core.AttachmentRegistered{}, ErrTooLarge
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	path := s.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return core.AttachmentRegistered{}, err
+	}
+	if err := os.Rename(upload.Name(), path); err != nil {
+		return core.AttachmentRegistered{}, err
+	}
+
+	attachmentID := "attachment-" + digest[:32]
+	registered, found, err := s.Lookup(ctx, attachmentID)
+	if err != nil || found {
+		return registered, err
+	}
+	registered = core.AttachmentRegistered{
+		CommandID:    attachmentID,
+		AttachmentID: attachmentID,
+		ContentHash:  contentHashPrefix + digest,
+		MIMEType:     mimeType,
+		SizeBytes:    size,
+	}
+	_, err = s.events.Append(ctx, core.AttachmentStreamID(attachmentID), 0, []any{registered}, metadata)
+	if errors.Is(err, store.ErrConcurrencyConflict) {
+		registered, _, err = s.Lookup(ctx, attachmentID)
+	}
+	return registered, err
+}
+
+// Lookup returns the registration of attachmentID, if the registry minted it.
+func (s *FileStore) Lookup(ctx context.Context, attachmentID string) (core.AttachmentRegistered, bool, error) {
+	records, version, err := s.events.Load(ctx, core.AttachmentStreamID(attachmentID))
+	if err != nil || version == 0 {
+		return core.AttachmentRegistered{}, false, err
+	}
+	registered, ok := records[0].Event.(core.AttachmentRegistered)
+	if !ok {
+		return core.AttachmentRegistered{}, false, errNotRegistration
+	}
+	return registered, true, nil
+}
+
+// Open returns the content of a registered attachment after checking it
+// against the recorded hash.
+func (s *FileStore) Open(ctx context.Context, attachmentID string) ([]byte, core.AttachmentRegistered, error) {
+	registered, found, err := s.Lookup(ctx, attachmentID)
+	if err != nil {
+		return nil, core.AttachmentRegistered{}, err
+	}
+	if !found {
+		return nil, core.AttachmentRegistered{}, ErrUnknownAttachment
+	}
+	digest, hashed := strings.CutPrefix(registered.ContentHash, contentHashPrefix)
+	if !hashed || len(digest) != sha256.Size*2 {
+		return nil, core.AttachmentRegistered{}, ErrCorruptAttachment
+	}
+	content, err := os.ReadFile(s.blobPath(digest))
+	if err != nil {
+		return nil, core.AttachmentRegistered{}, err
+	}
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != digest {
+		return nil, core.AttachmentRegistered{}, ErrCorruptAttachment
+	}
+	return content, registered, nil
+}
+
+func (s *FileStore) blobPath(digest string) string {
+	return filepath.Join(s.root, "sha256", digest[:2], digest)
+}
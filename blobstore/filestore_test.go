@@ -0,0 +1,148 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestFileStoreGivenContentWhenPutThenRegistersAttachmentInItsOwnStream(t *testing.T) {
+	ctx := context.Background()
+	events := store.NewInMemoryStore()
+	blobs := newFileStoreForTest(t, events)
+
+	uploaded := store.Metadata{Actor: store.Actor{Type: store.ActorHuman, ID: "person-ana"}, RecordedAt: time.Date(2026, time.February, 14, 12, 0, 0, 0, time.UTC)}
+	registered, err := blobs.Put(ctx, bytes.NewReader(pngHeader), "", uploaded)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if !strings.HasPrefix(registered.AttachmentID, "attachment-") {
+		t.Fatalf("attachment_id = %q, want attachment- prefix", registered.AttachmentID)
+	}
+	if registered.MIMEType != "image/png" || registered.SizeBytes != int64(len(pngHeader)) {
+		t.Fatalf("unexpected registration %+v", registered)
+	}
+	if !strings.HasPrefix(registered.ContentHash, "sha256:") {
+		t.Fatalf("content_hash = %q, want sha256: prefix", registered.ContentHash)
+	}
+
+	stored, version, err := events.LoadFrom(ctx, core.AttachmentStreamID(registered.AttachmentID), 1, 0)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if version != 1 || stored[0].Event != any(registered) || stored[0].Metadata != uploaded {
+		t.Fatalf("expected the registration at version 1 with its metadata, got %+v at %d", stored, version)
+	}
+
+	content, opened, err := blobs.Open(ctx, registered.AttachmentID)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if !bytes.Equal(content, pngHeader) || opened != registered {
+		t.Fatalf("open returned %q %+v", content, opened)
+	}
+}
+
+func TestFileStoreGivenSameContentWhenPutTwiceThenReturnsSameRegistration(t *testing.T) {
+	ctx := context.Background()
+	events := store.NewInMemoryStore()
+	blobs := newFileStoreForTest(t, events)
+
+	first, err := blobs.Put(ctx, strings.NewReader("lab report"), "text/plain; charset=utf-8", store.Metadata{})
+	if err != nil {
+		t.Fatalf("first put: %v", err)
+	}
+	second, err := blobs.Put(ctx, strings.NewReader("lab report"), "text/plain", store.Metadata{})
+	if err != nil {
+		t.Fatalf("second put: %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected the same registration, got %+v and %+v", first, second)
+	}
+	_, version, err := events.Load(ctx, core.AttachmentStreamID(first.AttachmentID))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if version != 1 {
+		t.Fatalf("version = %d, want 1", version)
+	}
+}
+
+func TestFileStoreGivenInvalidUploadWhenPutThenRegistersNothing(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		mimeType string
+		want     error
+	}{
+		{name: "too large", content: strings.Repeat("x", 17), mimeType: "text/plain", want: ErrTooLarge},
+		{name: "malformed mime type", content: "x", mimeType: "image/", want: ErrInvalidMIMEType},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			blobs, err := NewFileStore(root, store.NewInMemoryStore())
+			if err != nil {
+				t.Fatalf("new file store: %v", err)
+			}
+			blobs.WithMaxBytes(16)
+
+			_, err = blobs.Put(context.Background(), strings.NewReader(tc.content), tc.mimeType, store.Metadata{})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			leftovers, err := filepath.Glob(filepath.Join(root, ".upload-*"))
+			if err != nil {
+				t.Fatalf("glob: %v", err)
+			}
+			if len(leftovers) != 0 {
+				t.Fatalf("temporary uploads left behind: %v", leftovers)
+			}
+		})
+	}
+}
+
+func TestFileStoreGivenTamperedBlobWhenOpenThenReturnsCorruptAttachment(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs, err := NewFileStore(root, store.NewInMemoryStore())
+	if err != nil {
+		t.Fatalf("new file store: %v", err)
+	}
+
+	registered, err := blobs.Put(ctx, strings.NewReader("photo"), "image/jpeg", store.Metadata{})
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	digest := strings.TrimPrefix(registered.ContentHash, "sha256:")
+	if err := os.WriteFile(blobs.blobPath(digest), []byte("edited"), 0o644); err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+
+	if _, _, err := blobs.Open(ctx, registered.AttachmentID); !errors.Is(err, ErrCorruptAttachment) {
+		t.Fatalf("err = %v, want %v", err, ErrCorruptAttachment)
+	}
+	if _, _, err := blobs.Open(ctx, "attachment-never-minted"); !errors.Is(err, ErrUnknownAttachment) {
+		t.Fatalf("err = %v, want %v", err, ErrUnknownAttachment)
+	}
+}
+
+func newFileStoreForTest(t *testing.T, events store.EventStore) *FileStore {
+	t.Helper()
+	blobs, err := NewFileStore(t.TempDir(), events)
+	if err != nil {
+		t.Fatalf("new file store: %v", err)
+	}
+	return blobs
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	core "github.com/wastingnotime/zeroapps/core/household"
+	"github.com/wastingnotime/zeroapps/store"
+	svc "github.com/wastingnotime/zeroapps/svc/household"
+)
+
+func isHouseholdCommand(name string) bool {
+	switch name {
+	case "create-household", "add-cat", "remove-cat", "invite-caretaker", "remove-caretaker":
+		return true
+	default:
+		return false
+	}
+}
+
+func runHouseholdCommand(eventStore store.EventStore, name, aggregateID, commandID string, expected int, input commandInput) {
+	command, err := buildHouseholdCommand(name, commandID, input)
+	if err != nil {
+		fail(err)
+	}
+
+	if aggregateID == "" {
+		if name == "create-household" {
+			aggregateID = "household-" + commandID
+		} else {
+			fail(fmt.Errorf("aggregate-id is required"))
+		}
+	}
+
+	var expectedVersion *int
+	if expected >= 0 {
+		expectedVersion = &expected
+	}
+
+	result, err := svc.NewService(eventStore).HandleCommand(context.Background(), svc.CommandEnvelope{
+		AggregateID:     aggregateID,
+		Command:         command,
+		ExpectedVersion: expectedVersion,
+		Actor:           store.Actor{Type: input.actorType, ID: input.actorID},
+		CorrelationID:   input.correlationID,
+		CausationID:     input.causationID,
+	})
+	if err != nil {
+		fail(err)
+	}
+
+	if !result.Ok {
+		fmt.Printf("rejected: %s\n", result.Rejection.Error())
+		os.Exit(2)
+	}
+
+	fmt.Printf("ok: version=%d events=%d\n", result.NewVersion, len(result.Events))
+	for _, event := range result.Events {
+		fmt.Printf("- %s\n", householdEventSummary(event))
+	}
+}
+
+func buildHouseholdCommand(name, commandID string, input commandInput) (core.Command, error) {
+	switch name {
+	case "create-household":
+		return core.CreateHousehold{
+			CommandID: commandID,
+			Name:      input.name,
+			OwnerRef:  input.ownerRef,
+		}, nil
+	case "add-cat":
+		return core.AddCatToHousehold{
+			CommandID: commandID,
+			CatID:     input.catID,
+		}, nil
+	case "remove-cat":
+		return core.RemoveCatFromHousehold{
+			CommandID: commandID,
+			CatID:     input.catID,
+			Reason:    input.reason,
+		}, nil
+	case "invite-caretaker":
+		return core.InviteCaretaker{
+			CommandID:    commandID,
+			CaretakerRef: input.caretakerRef,
+		}, nil
+	case "remove-caretaker":
+		return core.RemoveCaretaker{
+			CommandID:    commandID,
+			CaretakerRef: input.caretakerRef,
+			Reason:       input.reason,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown cmd %q", name)
+	}
+}
+
+func householdEventSummary(event core.Event) string {
+	switch ev := event.(type) {
+	case core.HouseholdCreated:
+		return fmt.Sprintf("HouseholdCreated household_id=%s name=%s owner=%s", ev.HouseholdID, ev.Name, ev.OwnerRef)
+	case core.CatAddedToHousehold:
+		return fmt.Sprintf("CatAddedToHousehold household_id=%s cat_id=%s", ev.HouseholdID, ev.CatID)
+	case core.CatRemovedFromHousehold:
+		return fmt.Sprintf("CatRemovedFromHousehold household_id=%s cat_id=%s reason=%s", ev.HouseholdID, ev.CatID, ev.Reason)
+	case core.CaretakerInvited:
+		return fmt.Sprintf("CaretakerInvited household_id=%s caretaker=%s", ev.HouseholdID, ev.CaretakerRef)
+	case core.CaretakerRemoved:
+		return fmt.Sprintf("CaretakerRemoved household_id=%s caretaker=%s reason=%s", ev.HouseholdID, ev.CaretakerRef, ev.Reason)
+	default:
+		return fmt.Sprintf("%T", event)
+	}
+}
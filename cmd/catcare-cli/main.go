@@ -5,26 +5,92 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/wastingnotime/zeroapps/blobstore"
 	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/core/household"
 	projection "github.com/wastingnotime/zeroapps/projection/catcare"
 	"github.com/wastingnotime/zeroapps/store"
 	svc "github.com/wastingnotime/zeroapps/svc/catcare"
+	householdsvc "github.com/wastingnotime/zeroapps/svc/household"
 )
 
 func main() {
 	var (
-		commandName = flag.String("cmd", "", "command name: register|log-weight|list-registered")
-		dbPath      = flag.String("db", "catcare.db", "sqlite database path")
-		aggregateID = flag.String("aggregate-id", "", "aggregate id (cat id)")
-		commandID   = flag.String("command-id", "", "command id (required)")
-		expected    = flag.Int("expected-version", -1, "expected stream version (optional)")
-		name        = flag.String("name", "", "cat name (register)")
-		birthDate   = flag.String("birth-date", "", "birth date (register)")
-		at          = flag.String("at", "", "timestamp (log-weight)")
-		grams       = flag.Int("grams", 0, "grams (log-weight)")
-		notes       = flag.String("notes", "", "notes (log-weight)")
+		commandName   = flag.String("cmd", "", "command name: register|rename|mark-deceased|archive|transfer|register-microchip|log-weight|schedule|reschedule|complete|cancel|report-anomaly|resolve-anomaly|start-plan|end-plan|correct-weight|retract-weight|set-ideal-weight|record-vaccination|record-visit|prescribe|give-dose|set-diet|log-meal|list-registered|list-weights|list-vaccines|attach|create-household|add-cat|remove-cat|invite-caretaker|remove-caretaker|list-missed-doses|list-intake|history")
+		dbPath        = flag.String("db", "catcare.db", "sqlite database path")
+		aggregateID   = flag.String("aggregate-id", "", "aggregate id (cat id)")
+		commandID     = flag.String("command-id", "", "command id (required)")
+		expected      = flag.Int("expected-version", -1, "expected stream version (optional)")
+		allRejections = flag.Bool("all-rejections", false, "report every invalid field instead of the first")
+		snapshotEvery = flag.Int("snapshot-every", 100, "snapshot a cat's state every N events (0 disables)")
+		input         commandInput
 	)
+	flag.StringVar(&input.actorType, "actor-type", store.ActorHuman, "who issues the command: ai|human|system")
+	flag.StringVar(&input.actorID, "actor-id", "", "caller id recorded with the events")
+	flag.StringVar(&input.correlationID, "correlation-id", "", "flow the command belongs to (default: the causation id)")
+	flag.StringVar(&input.causationID, "causation-id", "", "message that caused the command (default: the command id)")
+	flag.StringVar(&input.name, "name", "", "cat name (register, rename); household name (create-household)")
+	flag.StringVar(&input.birthDate, "birth-date", "", "birth date (register)")
+	flag.StringVar(&input.at, "at", "", "timestamp (log-weight, correct-weight, complete, prescribe, give-dose, log-meal, record-visit)")
+	flag.IntVar(&input.grams, "grams", 0, "grams (log-weight, correct-weight; grams offered for log-meal)")
+	flag.StringVar(&input.amount, "amount", "", "weight amount in -unit, instead of -grams (log-weight, correct-weight)")
+	flag.IntVar(&input.bodyConditionScore, "bcs", 0, "body condition score 1-9 (log-weight, correct-weight, optional)")
+	flag.IntVar(&input.minGrams, "min-grams", 0, "ideal range lower bound in grams (set-ideal-weight)")
+	flag.IntVar(&input.maxGrams, "max-grams", 0, "ideal range upper bound in grams (set-ideal-weight)")
+	flag.StringVar(&input.notes, "notes", "", "notes (log-weight, schedule, complete)")
+	flag.StringVar(&input.kind, "kind", "", "care item kind: VACCINE|VET_APPOINTMENT|TREATMENT_STEP|OTHER (schedule)")
+	flag.StringVar(&input.title, "title", "", "care item title (schedule)")
+	flag.StringVar(&input.dueAt, "due-at", "", "due timestamp (schedule, reschedule)")
+	flag.StringVar(&input.itemID, "item-id", "", "care item id (reschedule, complete, cancel; appointment fulfilled by record-visit)")
+	flag.StringVar(&input.reason, "reason", "", "reason (cancel, correct-weight, retract-weight, record-visit, remove-cat, remove-caretaker)")
+	flag.StringVar(&input.caretakerRef, "caretaker", "", "caretaker reference (transfer, invite-caretaker, remove-caretaker)")
+	flag.StringVar(&input.diedOn, "died-on", "", "date of death YYYY-MM-DD (mark-deceased)")
+	flag.StringVar(&input.chipNumber, "chip", "", "ISO 11784 15-digit microchip number (register-microchip)")
+	flag.StringVar(&input.implantedOn, "implanted-on", "", "implant date YYYY-MM-DD (register-microchip, optional)")
+	flag.StringVar(&input.vaccineType, "vaccine-type", "", "vaccine type (record-vaccination)")
+	flag.StringVar(&input.manufacturer, "manufacturer", "", "vaccine manufacturer (record-vaccination)")
+	flag.StringVar(&input.lotNumber, "lot-number", "", "vaccine lot number (record-vaccination)")
+	flag.StringVar(&input.validUntil, "valid-until", "", "last valid day YYYY-MM-DD (record-vaccination)")
+	flag.StringVar(&input.clinicRef, "clinic", "", "clinic (record-vaccination, record-visit)")
+	flag.StringVar(&input.drugName, "drug", "", "drug name (prescribe)")
+	flag.Float64Var(&input.dose, "dose", 0, "dose amount (prescribe)")
+	flag.StringVar(&input.unit, "unit", "", "dose unit, e.g. mg or ml (prescribe); weight unit g|kg|lb (log-weight, correct-weight)")
+	flag.StringVar(&input.route, "route", "", "route: ORAL|TOPICAL|INJECTION|OPHTHALMIC|OTIC|INHALED|OTHER (prescribe)")
+	flag.IntVar(&input.intervalHours, "interval-hours", 0, "hours between doses (prescribe)")
+	flag.StringVar(&input.endsAt, "ends-at", "", "prescription end timestamp (prescribe, optional)")
+	flag.StringVar(&input.prescriptionID, "prescription-id", "", "prescription id (give-dose)")
+	flag.StringVar(&input.from, "from", "", "window start timestamp (list-missed-doses)")
+	flag.IntVar(&input.fromVersion, "from-version", 1, "first stream version to show (history)")
+	flag.IntVar(&input.limit, "limit", 0, "maximum number of events to show, 0 for all (history)")
+	flag.StringVar(&input.foodBrand, "food-brand", "", "food brand (set-diet)")
+	flag.IntVar(&input.dailyGrams, "daily-grams", 0, "planned grams per day (set-diet)")
+	flag.IntVar(&input.mealCount, "meals", 0, "planned meals per day (set-diet)")
+	flag.StringVar(&input.startsOn, "starts-on", "", "plan start date YYYY-MM-DD (set-diet)")
+	flag.IntVar(&input.gramsEaten, "eaten", 0, "grams eaten (log-meal)")
+	flag.StringVar(&input.vetName, "vet", "", "vet name (record-visit, set-ideal-weight)")
+	flag.StringVar(&input.diagnoses, "diagnoses", "", "comma-separated diagnosis codes (record-visit)")
+	flag.StringVar(&input.followUps, "follow-ups", "", "semicolon-separated KIND|title|due-at follow-ups (record-visit)")
+	flag.StringVar(&input.visitID, "visit-id", "", "vet visit id to link (complete, set-ideal-weight; optional)")
+	flag.StringVar(&input.entryID, "entry-id", "", "weight entry id (correct-weight, retract-weight)")
+	flag.StringVar(&input.planID, "plan-id", "", "treatment plan id (schedule optional, end-plan)")
+	flag.StringVar(&input.protocol, "protocol", "", "treatment protocol (start-plan)")
+	flag.StringVar(&input.outcome, "outcome", "", "treatment outcome (end-plan)")
+	flag.StringVar(&input.summary, "summary", "", "anomaly summary (report-anomaly)")
+	flag.StringVar(&input.severity, "severity", "", "anomaly severity: LOW|MEDIUM|HIGH|CRITICAL (report-anomaly)")
+	flag.StringVar(&input.tags, "tags", "", "comma-separated anomaly tags (report-anomaly)")
+	flag.StringVar(&input.anomalyID, "anomaly-id", "", "anomaly id (resolve-anomaly)")
+	flag.StringVar(&input.attachments, "attachments", "", "comma-separated attachment ids from attach (report-anomaly, record-visit)")
+	flag.StringVar(&input.file, "file", "", "file to store (attach)")
+	flag.StringVar(&input.mimeType, "mime", "", "mime type, sniffed when empty (attach)")
+	flag.StringVar(&input.blobDir, "blobs", "", "attachment blob directory (attach; default: blobs next to -db)")
+	flag.StringVar(&input.ownerRef, "owner", "", "owner reference (create-household)")
+	flag.StringVar(&input.catID, "cat-id", "", "cat aggregate id (add-cat, remove-cat)")
+	flag.StringVar(&input.repeatUnit, "repeat-unit", "", "recurrence unit: DAY|WEEK|MONTH|YEAR (schedule, optional)")
+	flag.IntVar(&input.repeatEvery, "repeat-every", 1, "recurrence interval (schedule, with -repeat-unit)")
 	flag.Parse()
 
 	if *commandName == "" {
@@ -32,7 +98,19 @@ func main() {
 	}
 
 	registeredCats := projection.NewRegisteredCats()
-	eventStore, err := store.NewSQLiteStore(*dbPath)
+	weightHistory := projection.NewWeightHistory()
+	vaccinationStatus := projection.NewVaccinationStatus()
+	medicationDoses := projection.NewMedicationDoses()
+	dailyIntake := projection.NewDailyIntake()
+	projectors := []svc.Projector{registeredCats, weightHistory, vaccinationStatus, medicationDoses, dailyIntake}
+	codec := store.NewRegistry()
+	if err := svc.RegisterEvents(codec); err != nil {
+		fail(err)
+	}
+	if err := householdsvc.RegisterEvents(codec); err != nil {
+		fail(err)
+	}
+	eventStore, err := store.NewSQLiteStore(*dbPath, codec)
 	if err != nil {
 		fail(err)
 	}
@@ -42,17 +120,143 @@ func main() {
 		}
 	}()
 
-	if err := eventStore.Replay(context.Background(), registeredCats); err != nil {
+	if err := eventStore.Replay(context.Background(), svc.Replayer(projectors...)); err != nil {
 		fail(err)
 	}
 
-	service := svc.NewService(eventStore, registeredCats)
+	service := svc.NewService(eventStore, projectors...).WithSnapshots(*snapshotEvery)
+	if *allRejections {
+		service.WithAggregateOptions(core.WithAllRejections())
+	}
 
 	if *commandName == "list-registered" {
 		cats := registeredCats.ListRegisteredCats()
 		fmt.Printf("registered_cats=%d\n", len(cats))
 		for _, cat := range cats {
-			fmt.Printf("- cat_id=%s name=%s birth_date=%s\n", cat.CatID, cat.Name, cat.BirthDate)
+			fmt.Printf("- cat_id=%s name=%s birth_date=%s status=%s\n", cat.CatID, cat.Name, cat.BirthDate, cat.Status)
+		}
+		return
+	}
+
+	if *commandName == "list-weights" {
+		if *aggregateID == "" {
+			fail(fmt.Errorf("aggregate-id is required"))
+		}
+		entries := weightHistory.ListWeightEntries(*aggregateID)
+		fmt.Printf("weight_entries=%d\n", len(entries))
+		for _, entry := range entries {
+			fmt.Printf("- entry_id=%s at=%s grams=%d bcs=%d corrected=%t\n", entry.EntryID, entry.At, entry.Grams, entry.BodyConditionScore, entry.Corrected)
+		}
+		return
+	}
+
+	if *commandName == "list-vaccines" {
+		if *aggregateID == "" {
+			fail(fmt.Errorf("aggregate-id is required"))
+		}
+		asOf := time.Now()
+		if input.at != "" {
+			parsed, err := time.Parse(time.RFC3339, input.at)
+			if err != nil {
+				fail(fmt.Errorf("at: %w", err))
+			}
+			asOf = parsed
+		}
+		statuses := vaccinationStatus.VaccinesAsOf(*aggregateID, asOf)
+		fmt.Printf("vaccines=%d as_of=%s\n", len(statuses), asOf.Format(time.RFC3339))
+		for _, status := range statuses {
+			fmt.Printf("- vaccine_type=%s lot_number=%s administered_at=%s valid_until=%s current=%t\n", status.VaccineType, status.LotNumber, status.AdministeredAt, status.ValidUntil, status.Current)
+		}
+		return
+	}
+
+	if *commandName == "list-intake" {
+		if *aggregateID == "" {
+			fail(fmt.Errorf("aggregate-id is required"))
+		}
+		days := dailyIntake.ListDailyIntake(*aggregateID)
+		fmt.Printf("intake_days=%d\n", len(days))
+		for _, day := range days {
+			fmt.Printf("- date=%s meals=%d offered=%d eaten=%d planned=%d percent_of_plan=%d\n", day.Date, day.Meals, day.GramsOffered, day.GramsEaten, day.PlannedGrams, day.PercentOfPlan)
+		}
+		return
+	}
+
+	if *commandName == "history" {
+		if *aggregateID == "" {
+			fail(fmt.Errorf("aggregate-id is required"))
+		}
+		shown := 0
+		for recorded, err := range store.ReadStream(context.Background(), eventStore, *aggregateID, input.fromVersion, 0) {
+			if err != nil {
+				fail(err)
+			}
+			if input.limit > 0 && shown == input.limit {
+				break
+			}
+			metadata := recorded.Metadata
+			fmt.Printf("- version=%d %s actor=%s:%s recorded_at=%s correlation_id=%s causation_id=%s\n",
+				recorded.Version, historySummary(recorded.Event), metadata.Actor.Type, metadata.Actor.ID,
+				formatRecordedAt(metadata.RecordedAt), metadata.CorrelationID, metadata.CausationID)
+			shown++
+		}
+		return
+	}
+
+	if *commandName == "attach" {
+		blobDir := input.blobDir
+		if blobDir == "" {
+			blobDir = filepath.Join(filepath.Dir(*dbPath), "blobs")
+		}
+		blobs, err := blobstore.NewFileStore(blobDir, eventStore)
+		if err != nil {
+			fail(err)
+		}
+		if input.file == "" {
+			fail(fmt.Errorf("file is required"))
+		}
+		file, err := os.Open(input.file)
+		if err != nil {
+			fail(err)
+		}
+		defer file.Close()
+		registered, err := blobs.Put(context.Background(), file, input.mimeType, store.Metadata{
+			Actor:         store.Actor{Type: input.actorType, ID: input.actorID},
+			RecordedAt:    time.Now(),
+			CorrelationID: input.correlationID,
+			CausationID:   input.causationID,
+		})
+		if err != nil {
+			fail(err)
+		}
+		fmt.Printf("ok: attachment_id=%s content_hash=%s mime=%s size=%d\n", registered.AttachmentID, registered.ContentHash, registered.MIMEType, registered.SizeBytes)
+		return
+	}
+
+	if *commandName == "list-missed-doses" {
+		if *aggregateID == "" {
+			fail(fmt.Errorf("aggregate-id is required"))
+		}
+		to := time.Now()
+		if input.at != "" {
+			parsed, err := time.Parse(time.RFC3339, input.at)
+			if err != nil {
+				fail(fmt.Errorf("at: %w", err))
+			}
+			to = parsed
+		}
+		from := to.Add(-7 * 24 * time.Hour)
+		if input.from != "" {
+			parsed, err := time.Parse(time.RFC3339, input.from)
+			if err != nil {
+				fail(fmt.Errorf("from: %w", err))
+			}
+			from = parsed
+		}
+		missed := medicationDoses.ListMissedDoses(*aggregateID, from, to)
+		fmt.Printf("missed_doses=%d from=%s to=%s\n", len(missed), from.Format(time.RFC3339), to.Format(time.RFC3339))
+		for _, dose := range missed {
+			fmt.Printf("- prescription_id=%s drug=%s due_at=%s\n", dose.PrescriptionID, dose.DrugName, dose.DueAt)
 		}
 		return
 	}
@@ -61,7 +265,12 @@ func main() {
 		usageAndExit()
 	}
 
-	command, err := buildCommand(*commandName, *commandID, *name, *birthDate, *at, *grams, *notes)
+	if isHouseholdCommand(*commandName) {
+		runHouseholdCommand(eventStore, *commandName, *aggregateID, *commandID, *expected, input)
+		return
+	}
+
+	command, err := buildCommand(*commandName, *commandID, input)
 	if err != nil {
 		fail(err)
 	}
@@ -83,13 +292,25 @@ func main() {
 		AggregateID:     *aggregateID,
 		Command:         command,
 		ExpectedVersion: expectedVersion,
+		Actor:           store.Actor{Type: input.actorType, ID: input.actorID},
+		CorrelationID:   input.correlationID,
+		CausationID:     input.causationID,
 	})
 	if err != nil {
 		fail(err)
 	}
 
+	if result.Clarification != nil {
+		fmt.Printf("needs clarification: %s: %s\n", result.Clarification.Field, result.Clarification.Question)
+		for _, candidate := range result.Clarification.Candidates {
+			fmt.Printf("- %s\n", candidate)
+		}
+		os.Exit(3)
+	}
 	if !result.Ok {
-		fmt.Printf("rejected: %s\n", result.Rejection.Error())
+		for _, rejection := range result.Rejections {
+			fmt.Printf("rejected: %s\n", rejection.Error())
+		}
 		os.Exit(2)
 	}
 
@@ -99,32 +320,380 @@ func main() {
 	}
 }
 
-func buildCommand(name, commandID, catName, birthDate, at string, grams int, notes string) (core.Command, error) {
+type commandInput struct {
+	name               string
+	birthDate          string
+	at                 string
+	grams              int
+	notes              string
+	kind               string
+	title              string
+	dueAt              string
+	itemID             string
+	reason             string
+	caretakerRef       string
+	diedOn             string
+	vaccineType        string
+	manufacturer       string
+	lotNumber          string
+	validUntil         string
+	clinicRef          string
+	entryID            string
+	planID             string
+	protocol           string
+	outcome            string
+	summary            string
+	severity           string
+	tags               string
+	anomalyID          string
+	repeatUnit         string
+	repeatEvery        int
+	drugName           string
+	dose               float64
+	unit               string
+	route              string
+	intervalHours      int
+	endsAt             string
+	prescriptionID     string
+	from               string
+	foodBrand          string
+	dailyGrams         int
+	mealCount          int
+	gramsEaten         int
+	startsOn           string
+	chipNumber         string
+	implantedOn        string
+	vetName            string
+	diagnoses          string
+	followUps          string
+	visitID            string
+	attachments        string
+	file               string
+	mimeType           string
+	blobDir            string
+	ownerRef           string
+	catID              string
+	amount             string
+	bodyConditionScore int
+	minGrams           int
+	maxGrams           int
+	fromVersion        int
+	limit              int
+	actorType          string
+	actorID            string
+	correlationID      string
+	causationID        string
+}
+
+func buildCommand(name, commandID string, input commandInput) (core.Command, error) {
 	switch name {
 	case "register":
 		return core.RegisterCat{
 			CommandID: commandID,
-			Name:      catName,
-			BirthDate: birthDate,
+			Name:      input.name,
+			BirthDate: input.birthDate,
+		}, nil
+	case "rename":
+		return core.RenameCat{
+			CommandID: commandID,
+			NewName:   input.name,
+		}, nil
+	case "mark-deceased":
+		return core.MarkCatDeceased{
+			CommandID: commandID,
+			DiedOn:    input.diedOn,
+			Notes:     input.notes,
+		}, nil
+	case "archive":
+		return core.ArchiveCat{
+			CommandID: commandID,
+			Reason:    input.reason,
+		}, nil
+	case "transfer":
+		return core.TransferCat{
+			CommandID:     commandID,
+			CaretakerRef:  input.caretakerRef,
+			TransferredAt: input.at,
+			Notes:         input.notes,
+		}, nil
+	case "register-microchip":
+		return core.RegisterMicrochip{
+			CommandID:   commandID,
+			ChipNumber:  input.chipNumber,
+			ImplantedOn: input.implantedOn,
 		}, nil
 	case "log-weight":
 		return core.LogWeight{
+			CommandID:          commandID,
+			At:                 input.at,
+			Grams:              input.grams,
+			Amount:             input.amount,
+			Unit:               input.unit,
+			BodyConditionScore: input.bodyConditionScore,
+			Notes:              input.notes,
+		}, nil
+	case "correct-weight":
+		return core.CorrectWeightEntry{
+			CommandID:          commandID,
+			EntryID:            input.entryID,
+			At:                 input.at,
+			Grams:              input.grams,
+			Amount:             input.amount,
+			Unit:               input.unit,
+			BodyConditionScore: input.bodyConditionScore,
+			Notes:              input.notes,
+			Reason:             input.reason,
+		}, nil
+	case "set-ideal-weight":
+		return core.SetIdealWeightRange{
+			CommandID: commandID,
+			MinGrams:  input.minGrams,
+			MaxGrams:  input.maxGrams,
+			VetName:   input.vetName,
+			VisitID:   input.visitID,
+			Notes:     input.notes,
+		}, nil
+	case "retract-weight":
+		return core.RetractWeightEntry{
+			CommandID: commandID,
+			EntryID:   input.entryID,
+			Reason:    input.reason,
+		}, nil
+	case "record-vaccination":
+		return core.RecordVaccination{
+			CommandID:      commandID,
+			VaccineType:    input.vaccineType,
+			Manufacturer:   input.manufacturer,
+			LotNumber:      input.lotNumber,
+			AdministeredAt: input.at,
+			ValidUntil:     input.validUntil,
+			ClinicRef:      input.clinicRef,
+		}, nil
+	case "prescribe":
+		return core.PrescribeMedication{
+			CommandID:     commandID,
+			DrugName:      input.drugName,
+			Dose:          input.dose,
+			Unit:          input.unit,
+			Route:         input.route,
+			IntervalHours: input.intervalHours,
+			StartsAt:      input.at,
+			EndsAt:        input.endsAt,
+		}, nil
+	case "give-dose":
+		return core.RecordDoseGiven{
+			CommandID:      commandID,
+			PrescriptionID: input.prescriptionID,
+			GivenAt:        input.at,
+			Notes:          input.notes,
+		}, nil
+	case "set-diet":
+		return core.SetDietPlan{
+			CommandID:  commandID,
+			FoodBrand:  input.foodBrand,
+			DailyGrams: input.dailyGrams,
+			MealCount:  input.mealCount,
+			StartsOn:   input.startsOn,
+		}, nil
+	case "log-meal":
+		return core.LogMeal{
+			CommandID:    commandID,
+			At:           input.at,
+			GramsOffered: input.grams,
+			GramsEaten:   input.gramsEaten,
+			Notes:        input.notes,
+		}, nil
+	case "record-visit":
+		var diagnoses []string
+		if input.diagnoses != "" {
+			diagnoses = strings.Split(input.diagnoses, ",")
+		}
+		followUps, err := parseFollowUps(input.followUps)
+		if err != nil {
+			return nil, err
+		}
+		return core.RecordVetVisit{
+			CommandID:         commandID,
+			VisitedAt:         input.at,
+			Clinic:            input.clinicRef,
+			VetName:           input.vetName,
+			Reason:            input.reason,
+			DiagnosisCodes:    diagnoses,
+			Notes:             input.notes,
+			FollowUps:         followUps,
+			AppointmentItemID: input.itemID,
+			AttachmentIDs:     splitList(input.attachments),
+		}, nil
+	case "schedule":
+		var recurrence *core.Recurrence
+		if input.repeatUnit != "" {
+			recurrence = &core.Recurrence{Unit: input.repeatUnit, Interval: input.repeatEvery}
+		}
+		return core.ScheduleCareItem{
+			CommandID:  commandID,
+			Kind:       input.kind,
+			Title:      input.title,
+			DueAt:      input.dueAt,
+			Notes:      input.notes,
+			Recurrence: recurrence,
+			PlanID:     input.planID,
+		}, nil
+	case "reschedule":
+		return core.RescheduleCareItem{
 			CommandID: commandID,
-			At:        at,
-			Grams:     grams,
-			Notes:     notes,
+			ItemID:    input.itemID,
+			NewDueAt:  input.dueAt,
+		}, nil
+	case "complete":
+		return core.CompleteCareItem{
+			CommandID:   commandID,
+			ItemID:      input.itemID,
+			CompletedAt: input.at,
+			Notes:       input.notes,
+			VisitID:     input.visitID,
+		}, nil
+	case "cancel":
+		return core.CancelCareItem{
+			CommandID: commandID,
+			ItemID:    input.itemID,
+			Reason:    input.reason,
+		}, nil
+	case "report-anomaly":
+		var tags []string
+		if input.tags != "" {
+			tags = strings.Split(input.tags, ",")
+		}
+		return core.ReportAnomaly{
+			CommandID:     commandID,
+			At:            input.at,
+			Summary:       input.summary,
+			Severity:      input.severity,
+			Tags:          tags,
+			Notes:         input.notes,
+			AttachmentIDs: splitList(input.attachments),
+		}, nil
+	case "resolve-anomaly":
+		return core.ResolveAnomaly{
+			CommandID:  commandID,
+			AnomalyID:  input.anomalyID,
+			ResolvedAt: input.at,
+			Notes:      input.notes,
+		}, nil
+	case "start-plan":
+		return core.StartTreatmentPlan{
+			CommandID: commandID,
+			Title:     input.title,
+			StartedAt: input.at,
+			Protocol:  input.protocol,
+			Notes:     input.notes,
+		}, nil
+	case "end-plan":
+		return core.EndTreatmentPlan{
+			CommandID: commandID,
+			PlanID:    input.planID,
+			EndedAt:   input.at,
+			Outcome:   input.outcome,
+			Notes:     input.notes,
 		}, nil
 	default:
 		return nil, fmt.Errorf("unknown cmd %q", name)
 	}
 }
 
+// parseFollowUps reads "KIND|title|due-at" entries separated by semicolons.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func parseFollowUps(value string) ([]core.VetFollowUp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var followUps []core.VetFollowUp
+	for _, entry := range strings.Split(value, ";") {
+		parts := strings.Split(entry, "|")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("follow-up %q: want KIND|title|due-at", entry)
+		}
+		followUps = append(followUps, core.VetFollowUp{Kind: parts[0], Title: parts[1], DueAt: parts[2]})
+	}
+	return followUps, nil
+}
+
+func formatRecordedAt(recordedAt time.Time) string {
+	if recordedAt.IsZero() {
+		return ""
+	}
+	return recordedAt.UTC().Format(time.RFC3339)
+}
+
+// historySummary describes an event of any domain stored in the file.
+func historySummary(event any) string {
+	switch typed := event.(type) {
+	case core.Event:
+		return eventSummary(typed)
+	case household.Event:
+		return householdEventSummary(typed)
+	default:
+		return fmt.Sprintf("%T", event)
+	}
+}
+
 func eventSummary(event core.Event) string {
 	switch ev := event.(type) {
 	case core.CatRegistered:
 		return fmt.Sprintf("CatRegistered cat_id=%s name=%s birth_date=%s", ev.CatID, ev.Name, ev.BirthDate)
+	case core.CatRenamed:
+		return fmt.Sprintf("CatRenamed cat_id=%s new_name=%s", ev.CatID, ev.NewName)
+	case core.CatMarkedDeceased:
+		return fmt.Sprintf("CatMarkedDeceased cat_id=%s died_on=%s", ev.CatID, ev.DiedOn)
+	case core.CatArchived:
+		return fmt.Sprintf("CatArchived cat_id=%s reason=%s", ev.CatID, ev.Reason)
+	case core.CatTransferred:
+		return fmt.Sprintf("CatTransferred cat_id=%s caretaker=%s transferred_at=%s", ev.CatID, ev.CaretakerRef, ev.TransferredAt)
+	case core.MicrochipRegistered:
+		return fmt.Sprintf("MicrochipRegistered cat_id=%s chip_number=%s", ev.CatID, ev.ChipNumber)
 	case core.WeightLogged:
-		return fmt.Sprintf("WeightLogged entry_id=%s at=%s grams=%d", ev.EntryID, ev.At, ev.Grams)
+		return fmt.Sprintf("WeightLogged entry_id=%s at=%s grams=%d bcs=%d", ev.EntryID, ev.At, ev.Grams, ev.BodyConditionScore)
+	case core.WeightEntryCorrected:
+		return fmt.Sprintf("WeightEntryCorrected entry_id=%s at=%s grams=%d bcs=%d", ev.EntryID, ev.At, ev.Grams, ev.BodyConditionScore)
+	case core.IdealWeightRangeSet:
+		return fmt.Sprintf("IdealWeightRangeSet min_grams=%d max_grams=%d vet=%s visit_id=%s", ev.MinGrams, ev.MaxGrams, ev.VetName, ev.VisitID)
+	case core.WeightEntryRetracted:
+		return fmt.Sprintf("WeightEntryRetracted entry_id=%s reason=%s", ev.EntryID, ev.Reason)
+	case core.VaccinationRecorded:
+		return fmt.Sprintf("VaccinationRecorded vaccination_id=%s vaccine_type=%s lot_number=%s valid_until=%s", ev.VaccinationID, ev.VaccineType, ev.LotNumber, ev.ValidUntil)
+	case core.MedicationPrescribed:
+		return fmt.Sprintf("MedicationPrescribed prescription_id=%s drug=%s dose=%g%s route=%s interval_hours=%d", ev.PrescriptionID, ev.DrugName, ev.Dose, ev.Unit, ev.Route, ev.IntervalHours)
+	case core.DoseGiven:
+		return fmt.Sprintf("DoseGiven dose_id=%s prescription_id=%s given_at=%s", ev.DoseID, ev.PrescriptionID, ev.GivenAt)
+	case core.DietPlanSet:
+		return fmt.Sprintf("DietPlanSet plan_id=%s food_brand=%s daily_grams=%d meals=%d starts_on=%s", ev.PlanID, ev.FoodBrand, ev.DailyGrams, ev.MealCount, ev.StartsOn)
+	case core.MealLogged:
+		return fmt.Sprintf("MealLogged meal_id=%s at=%s offered=%d eaten=%d", ev.MealID, ev.At, ev.GramsOffered, ev.GramsEaten)
+	case core.VetVisitRecorded:
+		return fmt.Sprintf("VetVisitRecorded visit_id=%s visited_at=%s clinic=%s diagnoses=%s follow_ups=%d attachments=%d", ev.VisitID, ev.VisitedAt, ev.Clinic, strings.Join(ev.DiagnosisCodes, ","), len(ev.FollowUpItemIDs), len(ev.AttachmentIDs))
+	case core.CareItemScheduled:
+		return fmt.Sprintf("CareItemScheduled item_id=%s kind=%s title=%s due_at=%s", ev.ItemID, ev.Kind, ev.Title, ev.DueAt)
+	case core.CareItemRescheduled:
+		return fmt.Sprintf("CareItemRescheduled item_id=%s new_due_at=%s", ev.ItemID, ev.NewDueAt)
+	case core.CareItemCompleted:
+		return fmt.Sprintf("CareItemCompleted item_id=%s completed_at=%s", ev.ItemID, ev.CompletedAt)
+	case core.CareItemCanceled:
+		return fmt.Sprintf("CareItemCanceled item_id=%s reason=%s", ev.ItemID, ev.Reason)
+	case core.AnomalyReported:
+		return fmt.Sprintf("AnomalyReported anomaly_id=%s at=%s severity=%s summary=%s attachments=%d", ev.AnomalyID, ev.At, ev.Severity, ev.Summary, len(ev.AttachmentIDs))
+	case core.AnomalyResolved:
+		return fmt.Sprintf("AnomalyResolved anomaly_id=%s resolved_at=%s", ev.AnomalyID, ev.ResolvedAt)
+	case core.TreatmentPlanStarted:
+		return fmt.Sprintf("TreatmentPlanStarted plan_id=%s title=%s started_at=%s", ev.PlanID, ev.Title, ev.StartedAt)
+	case core.TreatmentPlanUpdated:
+		return fmt.Sprintf("TreatmentPlanUpdated plan_id=%s", ev.PlanID)
+	case core.TreatmentPlanEnded:
+		return fmt.Sprintf("TreatmentPlanEnded plan_id=%s ended_at=%s outcome=%s", ev.PlanID, ev.EndedAt, ev.Outcome)
 	default:
 		return fmt.Sprintf("%T", event)
 	}
@@ -134,7 +703,18 @@ func usageAndExit() {
 	fmt.Println("Usage:")
 	fmt.Println("  catcare-cli -db ./catcare.db -cmd register -command-id cmd-1 -name Miso -birth-date 2023-01-01")
 	fmt.Println("  catcare-cli -db ./catcare.db -cmd log-weight -aggregate-id cat-cmd-1 -command-id cmd-2 -at 2026-02-14T10:00:00Z -grams 4200")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd log-weight -aggregate-id cat-cmd-1 -command-id cmd-8 -at 2026-02-21T10:00:00Z -amount 9.5 -unit lb -bcs 5")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd schedule -aggregate-id cat-cmd-1 -command-id cmd-3 -kind VACCINE -title Rabies -due-at 2026-03-01T09:00:00Z")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd complete -aggregate-id cat-cmd-1 -command-id cmd-4 -item-id item-cmd-3 -at 2026-03-01T09:30:00Z")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd attach -file ./lesion.jpg")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd report-anomaly -aggregate-id cat-cmd-1 -command-id cmd-5 -at 2026-03-02T08:00:00Z -summary \"Skin lesion\" -severity LOW -attachments attachment-<id>")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd create-household -command-id cmd-6 -name Home -owner person-ana")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd add-cat -aggregate-id household-cmd-6 -command-id cmd-7 -cat-id cat-cmd-1")
 	fmt.Println("  catcare-cli -db ./catcare.db -cmd list-registered")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd list-weights -aggregate-id cat-cmd-1")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd history -aggregate-id cat-cmd-1 -from-version 2 -limit 20")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd list-vaccines -aggregate-id cat-cmd-1 -at 2026-06-01T00:00:00Z")
+	fmt.Println("  catcare-cli -db ./catcare.db -cmd list-missed-doses -aggregate-id cat-cmd-1 -from 2026-02-01T00:00:00Z -at 2026-02-08T00:00:00Z")
 	os.Exit(1)
 }
 
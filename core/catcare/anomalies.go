@@ -0,0 +1,208 @@
+package catcare
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	SeverityLow      = "LOW"
+	SeverityMedium   = "MEDIUM"
+	SeverityHigh     = "HIGH"
+	SeverityCritical = "CRITICAL"
+)
+
+type Anomaly struct {
+	AnomalyID       string
+	At              string
+	Summary         string
+	Severity        string
+	Tags            []string
+	Notes           string
+	AttachmentIDs   []string
+	WeightEntryID   string
+	Resolved        bool
+	ResolvedAt      string
+	ResolutionNotes string
+}
+
+// ReportAnomaly may reference photos or documents by AttachmentIDs minted by
+// the adapter's attachment registry.
+type ReportAnomaly struct {
+	CommandID     string
+	At            string
+	Summary       string
+	Severity      string
+	Tags          []string
+	Notes         string
+	AttachmentIDs []string
+}
+
+func (c ReportAnomaly) commandName() string { return "ReportAnomaly" }
+func (c ReportAnomaly) commandID() string   { return c.CommandID }
+
+type ResolveAnomaly struct {
+	CommandID  string
+	AnomalyID  string
+	ResolvedAt string
+	Notes      string
+}
+
+func (c ResolveAnomaly) commandName() string { return "ResolveAnomaly" }
+func (c ResolveAnomaly) commandID() string   { return c.CommandID }
+
+// AnomalyReported carries WeightEntryID when the core flagged the anomaly
+// from a weight change.
+type AnomalyReported struct {
+	CommandID     string
+	AnomalyID     string
+	At            string
+	Summary       string
+	Severity      string
+	Tags          []string
+	Notes         string
+	AttachmentIDs []string
+	WeightEntryID string
+}
+
+func (e AnomalyReported) eventName() string { return "AnomalyReported" }
+func (e AnomalyReported) commandID() string { return e.CommandID }
+
+type AnomalyResolved struct {
+	CommandID  string
+	AnomalyID  string
+	ResolvedAt string
+	Notes      string
+}
+
+func (e AnomalyResolved) eventName() string { return "AnomalyResolved" }
+func (e AnomalyResolved) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideReportAnomaly(cmd ReportAnomaly) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	at := strings.TrimSpace(cmd.At)
+	if at == "" {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: "at"}) {
+			return nil, v.err()
+		}
+	} else if _, err := a.plausibleTimestamp("at", at); v.fail(err) {
+		return nil, v.err()
+	}
+	summary, err := a.requiredText("summary", cmd.Summary, CodeInvalidSummary)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if !validSeverity(cmd.Severity) {
+		if v.fail(Rejection{Code: CodeInvalidSeverity, Message: "must be one of LOW, MEDIUM, HIGH, CRITICAL", Field: "severity"}) {
+			return nil, v.err()
+		}
+	}
+	tags, err := a.textList("tags", cmd.Tags)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	attachmentIDs, err := a.attachmentIDs("attachment_ids", cmd.AttachmentIDs)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := AnomalyReported{
+		CommandID:     cmd.CommandID,
+		AnomalyID:     mintID("anomaly", cmd.CommandID),
+		At:            at,
+		Summary:       summary,
+		Severity:      cmd.Severity,
+		Tags:          tags,
+		Notes:         notes,
+		AttachmentIDs: attachmentIDs,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideResolveAnomaly(cmd ResolveAnomaly) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	if strings.TrimSpace(cmd.AnomalyID) == "" {
+		return nil, Rejection{Code: CodeInvalidAnomalyID, Message: "must not be empty", Field: "anomaly_id"}
+	}
+	anomaly, exists := a.Anomalies[cmd.AnomalyID]
+	if !exists {
+		return nil, Rejection{Code: CodeUnknownAnomaly, Message: "anomaly does not exist", Field: "anomaly_id"}
+	}
+	if anomaly.Resolved {
+		return nil, Rejection{Code: CodeAnomalyResolved, Message: "anomaly already resolved", Field: "anomaly_id"}
+	}
+	v := a.validation()
+	resolvedAt := strings.TrimSpace(cmd.ResolvedAt)
+	resolved, err := a.plausibleTimestamp("resolved_at", resolvedAt)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err == nil {
+		if reported, err := time.Parse(time.RFC3339, anomaly.At); err == nil && resolved.Before(reported) {
+			if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the report time", Field: "resolved_at"}) {
+				return nil, v.err()
+			}
+		}
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := AnomalyResolved{
+		CommandID:  cmd.CommandID,
+		AnomalyID:  cmd.AnomalyID,
+		ResolvedAt: resolvedAt,
+		Notes:      notes,
+	}
+	return []Event{event}, nil
+}
+
+func validSeverity(severity string) bool {
+	switch severity {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeTags trims tags, drops blanks and keeps the first occurrence of
+// each tag so the emitted event is stable for the same input.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, exists := seen[tag]; exists {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
@@ -0,0 +1,255 @@
+package catcare
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportAnomalyGivenRegisteredCatWhenReportThenEmitsAnomalyReported(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(ReportAnomaly{
+		CommandID: "cmd-anomaly-1",
+		At:        "2026-02-14T07:30:00Z",
+		Summary:   " Vomited after breakfast ",
+		Severity:  SeverityMedium,
+		Tags:      []string{"vomiting", " ", "digestive", "vomiting"},
+	})
+	if err != nil {
+		t.Fatalf("decide report anomaly: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	event, ok := events[0].(AnomalyReported)
+	if !ok {
+		t.Fatalf("expected AnomalyReported, got %T", events[0])
+	}
+	if event.AnomalyID != "anomaly-cmd-anomaly-1" {
+		t.Fatalf("expected deterministic anomaly id, got %q", event.AnomalyID)
+	}
+	if event.Summary != "Vomited after breakfast" {
+		t.Fatalf("expected trimmed summary, got %q", event.Summary)
+	}
+	if len(event.Tags) != 2 || event.Tags[0] != "vomiting" || event.Tags[1] != "digestive" {
+		t.Fatalf("expected normalized tags, got %v", event.Tags)
+	}
+
+	if err := aggregate.Apply(event); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if _, exists := aggregate.Anomalies[event.AnomalyID]; !exists {
+		t.Fatalf("expected anomaly %q in state", event.AnomalyID)
+	}
+}
+
+func TestReportAnomalyGivenRegisteredCatWhenReportInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  ReportAnomaly
+		code string
+	}{
+		{
+			name: "unknown severity",
+			cmd: ReportAnomaly{
+				CommandID: "cmd-anomaly-severity",
+				At:        "2026-02-14T07:30:00Z",
+				Summary:   "Limping",
+				Severity:  "SEVERE",
+			},
+			code: CodeInvalidSeverity,
+		},
+		{
+			name: "blank summary",
+			cmd: ReportAnomaly{
+				CommandID: "cmd-anomaly-summary",
+				At:        "2026-02-14T07:30:00Z",
+				Severity:  SeverityLow,
+			},
+			code: CodeInvalidSummary,
+		},
+		{
+			name: "unparseable time",
+			cmd: ReportAnomaly{
+				CommandID: "cmd-anomaly-at",
+				At:        "this morning",
+				Summary:   "Limping",
+				Severity:  SeverityLow,
+			},
+			code: CodeInvalidDate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestResolveAnomalyGivenReportedAnomalyWhenResolveThenEmitsAnomalyResolved(t *testing.T) {
+	aggregate, err := LoadFrom(append(registeredCatEvents(), AnomalyReported{
+		CommandID: "cmd-anomaly",
+		AnomalyID: "anomaly-cmd-anomaly",
+		At:        "2026-02-14T07:30:00Z",
+		Summary:   "Limping",
+		Severity:  SeverityHigh,
+	}))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(ResolveAnomaly{
+		CommandID:  "cmd-resolve",
+		AnomalyID:  "anomaly-cmd-anomaly",
+		ResolvedAt: "2026-02-16T10:00:00Z",
+		Notes:      "walking normally",
+	})
+	if err != nil {
+		t.Fatalf("decide resolve anomaly: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if _, ok := events[0].(AnomalyResolved); !ok {
+		t.Fatalf("expected AnomalyResolved, got %T", events[0])
+	}
+
+	if err := aggregate.Apply(events[0]); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if !aggregate.Anomalies["anomaly-cmd-anomaly"].Resolved {
+		t.Fatal("expected anomaly to be resolved")
+	}
+}
+
+func TestResolveAnomalyGivenInvalidResolutionWhenResolveThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(append(registeredCatEvents(),
+		AnomalyReported{
+			CommandID: "cmd-anomaly-open",
+			AnomalyID: "anomaly-cmd-anomaly-open",
+			At:        "2026-02-14T07:30:00Z",
+			Summary:   "Limping",
+			Severity:  SeverityHigh,
+		},
+		AnomalyReported{
+			CommandID: "cmd-anomaly-closed",
+			AnomalyID: "anomaly-cmd-anomaly-closed",
+			At:        "2026-02-10T07:30:00Z",
+			Summary:   "Sneezing",
+			Severity:  SeverityLow,
+		},
+		AnomalyResolved{
+			CommandID:  "cmd-resolve",
+			AnomalyID:  "anomaly-cmd-anomaly-closed",
+			ResolvedAt: "2026-02-12T07:30:00Z",
+		},
+	))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  ResolveAnomaly
+		code string
+	}{
+		{
+			name: "unknown anomaly",
+			cmd: ResolveAnomaly{
+				CommandID:  "cmd-resolve-unknown",
+				AnomalyID:  "anomaly-missing",
+				ResolvedAt: "2026-02-16T10:00:00Z",
+			},
+			code: CodeUnknownAnomaly,
+		},
+		{
+			name: "already resolved",
+			cmd: ResolveAnomaly{
+				CommandID:  "cmd-resolve-again",
+				AnomalyID:  "anomaly-cmd-anomaly-closed",
+				ResolvedAt: "2026-02-16T10:00:00Z",
+			},
+			code: CodeAnomalyResolved,
+		},
+		{
+			name: "resolved before reported",
+			cmd: ResolveAnomaly{
+				CommandID:  "cmd-resolve-early",
+				AnomalyID:  "anomaly-cmd-anomaly-open",
+				ResolvedAt: "2026-02-14T07:29:59Z",
+			},
+			code: CodeInvalidDate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestAnomalyTimesGivenReferenceTimeWhenInTheFutureThenRejectsImplausibleDate(t *testing.T) {
+	reference := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	given := append(registeredCatEvents(), AnomalyReported{
+		CommandID: "cmd-anomaly-1",
+		AnomalyID: "anomaly-cmd-anomaly-1",
+		At:        "2026-02-14T07:30:00Z",
+		Summary:   "Vomited after breakfast",
+		Severity:  SeverityMedium,
+	})
+	aggregate, err := LoadFrom(given, WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		cmd   Command
+		field string
+	}{
+		{name: "reported in the future", cmd: ReportAnomaly{CommandID: "cmd-2", At: "2999-01-01T00:00:00Z", Summary: "Limping", Severity: SeverityLow}, field: "at"},
+		{name: "reported before the date window", cmd: ReportAnomaly{CommandID: "cmd-2", At: "1900-01-01T00:00:00Z", Summary: "Limping", Severity: SeverityLow}, field: "at"},
+		{name: "resolved in the future", cmd: ResolveAnomaly{CommandID: "cmd-2", AnomalyID: "anomaly-cmd-anomaly-1", ResolvedAt: "2999-01-01T00:00:00Z"}, field: "resolved_at"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			rejection, ok := err.(Rejection)
+			if !ok || rejection.Code != CodeImplausibleDate || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %v", CodeImplausibleDate, tc.field, err)
+			}
+		})
+	}
+}
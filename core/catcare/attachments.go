@@ -0,0 +1,91 @@
+package catcare
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AttachmentRegistered records a blob (a photo, a lab report, ...) stored by
+// the adapter's attachment registry. It is appended by the registry to the
+// stream AttachmentStreamID(AttachmentID), never by CatCare, and is the only
+// proof that an attachment ID was minted.
+type AttachmentRegistered struct {
+	CommandID    string
+	AttachmentID string
+	ContentHash  string
+	MIMEType     string
+	SizeBytes    int64
+}
+
+func (e AttachmentRegistered) eventName() string { return "AttachmentRegistered" }
+func (e AttachmentRegistered) commandID() string { return e.CommandID }
+
+const attachmentStreamPrefix = "attachment/"
+
+// AttachmentStreamID is the stream holding the registration of attachmentID.
+func AttachmentStreamID(attachmentID string) string {
+	return attachmentStreamPrefix + attachmentID
+}
+
+// WithKnownAttachments lists the attachment IDs the adapter's registry has
+// minted. Commands may only reference these; the service resolves them from
+// the registry's streams before deciding.
+func WithKnownAttachments(attachmentIDs ...string) Option {
+	return func(a *CatCare) {
+		a.knownAttachments = map[string]struct{}{}
+		for _, attachmentID := range attachmentIDs {
+			a.knownAttachments[attachmentID] = struct{}{}
+		}
+	}
+}
+
+// AttachmentIDs returns the attachment IDs command references, trimmed and
+// without empty values or duplicates, so adapters can look up exactly the IDs
+// Decide checks.
+func AttachmentIDs(command Command) []string {
+	var values []string
+	switch cmd := command.(type) {
+	case ReportAnomaly:
+		values = cmd.AttachmentIDs
+	case RecordVetVisit:
+		values = cmd.AttachmentIDs
+	}
+
+	var attachmentIDs []string
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		attachmentID := strings.TrimSpace(value)
+		if _, exists := seen[attachmentID]; exists || attachmentID == "" {
+			continue
+		}
+		seen[attachmentID] = struct{}{}
+		attachmentIDs = append(attachmentIDs, attachmentID)
+	}
+	return attachmentIDs
+}
+
+// attachmentIDs rejects empty and unknown IDs and drops duplicates, keeping
+// the first occurrence.
+func (a *CatCare) attachmentIDs(field string, values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	seen := map[string]struct{}{}
+	attachmentIDs := make([]string, 0, len(values))
+	for index, value := range values {
+		entryField := field + "[" + strconv.Itoa(index) + "]"
+		attachmentID := strings.TrimSpace(value)
+		if attachmentID == "" {
+			return nil, Rejection{Code: CodeInvalidAttachmentID, Message: "must not be empty", Field: entryField}
+		}
+		if _, known := a.knownAttachments[attachmentID]; !known {
+			return nil, Rejection{Code: CodeUnknownAttachment, Message: "attachment was not registered", Field: entryField}
+		}
+		if _, exists := seen[attachmentID]; exists {
+			continue
+		}
+		seen[attachmentID] = struct{}{}
+		attachmentIDs = append(attachmentIDs, attachmentID)
+	}
+	return attachmentIDs, nil
+}
@@ -0,0 +1,88 @@
+package catcare
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReportAnomalyGivenKnownAttachmentsWhenReportThenCarriesDeduplicatedIDs(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithKnownAttachments("attachment-a", "attachment-b"))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(ReportAnomaly{
+		CommandID:     "cmd-2",
+		At:            "2026-02-10T08:00:00Z",
+		Summary:       "Skin lesion on left ear",
+		Severity:      SeverityMedium,
+		AttachmentIDs: []string{"attachment-b", " attachment-a ", "attachment-b"},
+	})
+	if err != nil {
+		t.Fatalf("decide report anomaly: %v", err)
+	}
+	reported := events[0].(AnomalyReported)
+	if want := []string{"attachment-b", "attachment-a"}; !reflect.DeepEqual(reported.AttachmentIDs, want) {
+		t.Fatalf("attachment ids = %v, want %v", reported.AttachmentIDs, want)
+	}
+	if err := aggregate.Apply(reported); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := aggregate.Anomalies[reported.AnomalyID].AttachmentIDs; !reflect.DeepEqual(got, reported.AttachmentIDs) {
+		t.Fatalf("anomaly attachment ids = %v", got)
+	}
+}
+
+func TestDecideGivenAttachmentNotMintedByRegistryWhenReferencedThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithKnownAttachments("attachment-a"))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		command Command
+		code    string
+		field   string
+	}{
+		{
+			name:    "anomaly with invented id",
+			command: ReportAnomaly{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Summary: "Lesion", Severity: SeverityLow, AttachmentIDs: []string{"attachment-a", "photo-1"}},
+			code:    CodeUnknownAttachment,
+			field:   "attachment_ids[1]",
+		},
+		{
+			name:    "vet visit with invented id",
+			command: RecordVetVisit{CommandID: "cmd-2", VisitedAt: "2026-02-10T08:00:00Z", Clinic: "Clinic", Reason: "Checkup", AttachmentIDs: []string{"attachment-z"}},
+			code:    CodeUnknownAttachment,
+			field:   "attachment_ids[0]",
+		},
+		{
+			name:    "blank id",
+			command: RecordVetVisit{CommandID: "cmd-2", VisitedAt: "2026-02-10T08:00:00Z", Clinic: "Clinic", Reason: "Checkup", AttachmentIDs: []string{" "}},
+			code:    CodeInvalidAttachmentID,
+			field:   "attachment_ids[0]",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.command)
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %v", err)
+			}
+			if rejection.Code != tc.code || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %q on %q", tc.code, tc.field, rejection.Code, rejection.Field)
+			}
+		})
+	}
+}
+
+func TestAttachmentIDsGivenPaddedAndRepeatedIDsWhenListedThenMatchesWhatDecideChecks(t *testing.T) {
+	command := RecordVetVisit{CommandID: "cmd-2", AttachmentIDs: []string{" attachment-b ", "", "attachment-a", "attachment-b"}}
+
+	if got, want := AttachmentIDs(command), []string{"attachment-b", "attachment-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("AttachmentIDs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,321 @@
+package catcare
+
+import "strings"
+
+const (
+	CareItemKindVaccine        = "VACCINE"
+	CareItemKindVetAppointment = "VET_APPOINTMENT"
+	CareItemKindTreatmentStep  = "TREATMENT_STEP"
+	CareItemKindOther          = "OTHER"
+)
+
+const (
+	CareItemStatusScheduled = "scheduled"
+	CareItemStatusCompleted = "completed"
+	CareItemStatusCanceled  = "canceled"
+)
+
+type CareItem struct {
+	ItemID       string
+	Kind         string
+	Title        string
+	DueAt        string
+	Notes        string
+	Recurrence   *Recurrence
+	PlanID       string
+	Status       string
+	CompletedAt  string
+	CancelReason string
+	// FollowUpOfVisitID is the vet visit that requested this item.
+	FollowUpOfVisitID string
+	// VisitID links a completed VET_APPOINTMENT item to its visit record.
+	VisitID string
+}
+
+type ScheduleCareItem struct {
+	CommandID  string
+	Kind       string
+	Title      string
+	DueAt      string
+	Notes      string
+	Recurrence *Recurrence
+	PlanID     string
+}
+
+func (c ScheduleCareItem) commandName() string { return "ScheduleCareItem" }
+func (c ScheduleCareItem) commandID() string   { return c.CommandID }
+
+type RescheduleCareItem struct {
+	CommandID string
+	ItemID    string
+	NewDueAt  string
+}
+
+func (c RescheduleCareItem) commandName() string { return "RescheduleCareItem" }
+func (c RescheduleCareItem) commandID() string   { return c.CommandID }
+
+// CompleteCareItem closes a scheduled item. VisitID optionally links a
+// VET_APPOINTMENT item to the visit that fulfilled it.
+type CompleteCareItem struct {
+	CommandID   string
+	ItemID      string
+	CompletedAt string
+	Notes       string
+	VisitID     string
+}
+
+func (c CompleteCareItem) commandName() string { return "CompleteCareItem" }
+func (c CompleteCareItem) commandID() string   { return c.CommandID }
+
+type CancelCareItem struct {
+	CommandID string
+	ItemID    string
+	Reason    string
+}
+
+func (c CancelCareItem) commandName() string { return "CancelCareItem" }
+func (c CancelCareItem) commandID() string   { return c.CommandID }
+
+type CareItemScheduled struct {
+	CommandID         string
+	ItemID            string
+	Kind              string
+	Title             string
+	DueAt             string
+	Notes             string
+	Recurrence        *Recurrence
+	PlanID            string
+	FollowsItemID     string
+	FollowUpOfVisitID string
+}
+
+func (e CareItemScheduled) eventName() string { return "CareItemScheduled" }
+func (e CareItemScheduled) commandID() string { return e.CommandID }
+
+type CareItemRescheduled struct {
+	CommandID string
+	ItemID    string
+	NewDueAt  string
+}
+
+func (e CareItemRescheduled) eventName() string { return "CareItemRescheduled" }
+func (e CareItemRescheduled) commandID() string { return e.CommandID }
+
+type CareItemCompleted struct {
+	CommandID   string
+	ItemID      string
+	CompletedAt string
+	Notes       string
+	VisitID     string
+}
+
+func (e CareItemCompleted) eventName() string { return "CareItemCompleted" }
+func (e CareItemCompleted) commandID() string { return e.CommandID }
+
+type CareItemCanceled struct {
+	CommandID string
+	ItemID    string
+	Reason    string
+}
+
+func (e CareItemCanceled) eventName() string { return "CareItemCanceled" }
+func (e CareItemCanceled) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideScheduleCareItem(cmd ScheduleCareItem) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	if !validCareItemKind(cmd.Kind) {
+		if v.fail(Rejection{Code: CodeInvalidKind, Message: "must be one of VACCINE, VET_APPOINTMENT, TREATMENT_STEP, OTHER", Field: "kind"}) {
+			return nil, v.err()
+		}
+	}
+	title, err := a.requiredText("title", cmd.Title, CodeInvalidTitle)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	dueAt := strings.TrimSpace(cmd.DueAt)
+	if dueAt == "" {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: "due_at"}) {
+			return nil, v.err()
+		}
+	} else if _, err := a.scheduledTimestamp("due_at", dueAt); v.fail(err) {
+		return nil, v.err()
+	}
+	if v.fail(validateRecurrence(cmd.Recurrence)) {
+		return nil, v.err()
+	}
+	if cmd.PlanID != "" {
+		if _, err := a.activeTreatmentPlan(cmd.PlanID); v.fail(err) {
+			return nil, v.err()
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	var recurrence *Recurrence
+	if cmd.Recurrence != nil {
+		recurrence = &Recurrence{
+			Unit:     cmd.Recurrence.Unit,
+			Interval: cmd.Recurrence.Interval,
+			Anchor:   dueAt,
+		}
+	}
+
+	event := CareItemScheduled{
+		CommandID:  cmd.CommandID,
+		ItemID:     mintID("item", cmd.CommandID),
+		Kind:       cmd.Kind,
+		Title:      title,
+		DueAt:      dueAt,
+		Notes:      notes,
+		Recurrence: recurrence,
+		PlanID:     cmd.PlanID,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideRescheduleCareItem(cmd RescheduleCareItem) ([]Event, error) {
+	if _, err := a.openCareItem(cmd.ItemID); err != nil {
+		return nil, err
+	}
+	v := a.validation()
+	newDueAt := strings.TrimSpace(cmd.NewDueAt)
+	if _, err := a.scheduledTimestamp("new_due_at", newDueAt); v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := CareItemRescheduled{
+		CommandID: cmd.CommandID,
+		ItemID:    cmd.ItemID,
+		NewDueAt:  newDueAt,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideCompleteCareItem(cmd CompleteCareItem) ([]Event, error) {
+	item, err := a.openCareItem(cmd.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	v := a.validation()
+	completedAt := strings.TrimSpace(cmd.CompletedAt)
+	if _, err := a.plausibleTimestamp("completed_at", completedAt); v.fail(err) {
+		return nil, v.err()
+	}
+	visitID := strings.TrimSpace(cmd.VisitID)
+	if visitID != "" {
+		if _, exists := a.VetVisits[visitID]; !exists {
+			if v.fail(Rejection{Code: CodeUnknownVetVisit, Message: "vet visit does not exist", Field: "visit_id"}) {
+				return nil, v.err()
+			}
+		}
+		if item.Kind != CareItemKindVetAppointment {
+			if v.fail(Rejection{Code: CodeNotVetAppointment, Message: "only VET_APPOINTMENT items link to a visit", Field: "item_id"}) {
+				return nil, v.err()
+			}
+		}
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return completeCareItem(item, cmd.CommandID, completedAt, notes, visitID)
+}
+
+// completeCareItem emits the completion of item and, for recurring items, the
+// next occurrence minted from commandID.
+func completeCareItem(item CareItem, commandID string, completedAt string, notes string, visitID string) ([]Event, error) {
+	event := CareItemCompleted{
+		CommandID:   commandID,
+		ItemID:      item.ItemID,
+		CompletedAt: completedAt,
+		Notes:       notes,
+		VisitID:     visitID,
+	}
+	if item.Recurrence == nil {
+		return []Event{event}, nil
+	}
+
+	nextDueAt, err := nextOccurrence(*item.Recurrence, item.DueAt)
+	if err != nil {
+		return nil, Rejection{Code: CodeInvalidRecurrence, Message: "cannot compute next occurrence", Field: "item_id"}
+	}
+	next := CareItemScheduled{
+		CommandID:     commandID,
+		ItemID:        mintID("item", commandID),
+		Kind:          item.Kind,
+		Title:         item.Title,
+		DueAt:         nextDueAt,
+		Notes:         item.Notes,
+		Recurrence:    item.Recurrence,
+		PlanID:        item.PlanID,
+		FollowsItemID: item.ItemID,
+	}
+	return []Event{event, next}, nil
+}
+
+func (a *CatCare) decideCancelCareItem(cmd CancelCareItem) ([]Event, error) {
+	if _, err := a.openCareItem(cmd.ItemID); err != nil {
+		return nil, err
+	}
+	v := a.validation()
+	reason, err := a.text("reason", cmd.Reason)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := CareItemCanceled{
+		CommandID: cmd.CommandID,
+		ItemID:    cmd.ItemID,
+		Reason:    reason,
+	}
+	return []Event{event}, nil
+}
+
+// openCareItem returns the referenced care item if it exists and is still
+// scheduled; completed and canceled items are terminal.
+func (a *CatCare) openCareItem(itemID string) (CareItem, error) {
+	if !a.Registered {
+		return CareItem{}, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	if strings.TrimSpace(itemID) == "" {
+		return CareItem{}, Rejection{Code: CodeInvalidItemID, Message: "must not be empty", Field: "item_id"}
+	}
+	item, exists := a.CareItems[itemID]
+	if !exists {
+		return CareItem{}, Rejection{Code: CodeUnknownCareItem, Message: "care item does not exist", Field: "item_id"}
+	}
+	switch item.Status {
+	case CareItemStatusCompleted:
+		return CareItem{}, Rejection{Code: CodeCareItemCompleted, Message: "care item already completed", Field: "item_id"}
+	case CareItemStatusCanceled:
+		return CareItem{}, Rejection{Code: CodeCareItemCanceled, Message: "care item already canceled", Field: "item_id"}
+	}
+	return item, nil
+}
+
+func validCareItemKind(kind string) bool {
+	switch kind {
+	case CareItemKindVaccine, CareItemKindVetAppointment, CareItemKindTreatmentStep, CareItemKindOther:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,329 @@
+package catcare
+
+import (
+	"testing"
+	"time"
+)
+
+func registeredCatEvents() []Event {
+	return []Event{
+		CatRegistered{
+			CommandID: "cmd-register",
+			CatID:     "cat-cmd-register",
+			Name:      "Miso",
+		},
+	}
+}
+
+func TestScheduleCareItemGivenRegisteredCatWhenScheduleThenEmitsCareItemScheduled(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(ScheduleCareItem{
+		CommandID: "cmd-schedule-1",
+		Kind:      CareItemKindVaccine,
+		Title:     " Rabies booster ",
+		DueAt:     "2026-03-01T09:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("decide schedule care item: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	event, ok := events[0].(CareItemScheduled)
+	if !ok {
+		t.Fatalf("expected CareItemScheduled, got %T", events[0])
+	}
+	if event.ItemID != "item-cmd-schedule-1" {
+		t.Fatalf("expected deterministic item id, got %q", event.ItemID)
+	}
+	if event.Title != "Rabies booster" {
+		t.Fatalf("expected trimmed title, got %q", event.Title)
+	}
+
+	if err := aggregate.Apply(event); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	item, exists := aggregate.CareItems[event.ItemID]
+	if !exists {
+		t.Fatalf("expected care item %q in state", event.ItemID)
+	}
+	if item.Status != CareItemStatusScheduled {
+		t.Fatalf("expected status %q, got %q", CareItemStatusScheduled, item.Status)
+	}
+}
+
+func TestScheduleCareItemGivenRegisteredCatWhenScheduleInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  ScheduleCareItem
+		code string
+	}{
+		{
+			name: "unknown kind",
+			cmd: ScheduleCareItem{
+				CommandID: "cmd-schedule-kind",
+				Kind:      "GROOMING",
+				Title:     "Brush",
+				DueAt:     "2026-03-01T09:00:00Z",
+			},
+			code: CodeInvalidKind,
+		},
+		{
+			name: "blank title",
+			cmd: ScheduleCareItem{
+				CommandID: "cmd-schedule-title",
+				Kind:      CareItemKindOther,
+				Title:     "  ",
+				DueAt:     "2026-03-01T09:00:00Z",
+			},
+			code: CodeInvalidTitle,
+		},
+		{
+			name: "missing due date",
+			cmd: ScheduleCareItem{
+				CommandID: "cmd-schedule-due",
+				Kind:      CareItemKindVetAppointment,
+				Title:     "Checkup",
+			},
+			code: CodeInvalidDate,
+		},
+		{
+			name: "free-form due date",
+			cmd: ScheduleCareItem{
+				CommandID: "cmd-schedule-free-form",
+				Kind:      CareItemKindVetAppointment,
+				Title:     "Checkup",
+				DueAt:     "next tuesday",
+			},
+			code: CodeInvalidDate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestCareItemLifecycleGivenScheduledItemWhenRescheduleCompleteOrCancelThenEmitsEvent(t *testing.T) {
+	given := append(registeredCatEvents(), CareItemScheduled{
+		CommandID: "cmd-schedule",
+		ItemID:    "item-cmd-schedule",
+		Kind:      CareItemKindVaccine,
+		Title:     "Rabies booster",
+		DueAt:     "2026-03-01T09:00:00Z",
+	})
+
+	cases := []struct {
+		name     string
+		cmd      Command
+		expected string
+	}{
+		{
+			name: "reschedule",
+			cmd: RescheduleCareItem{
+				CommandID: "cmd-reschedule",
+				ItemID:    "item-cmd-schedule",
+				NewDueAt:  "2026-03-08T09:00:00Z",
+			},
+			expected: "CareItemRescheduled",
+		},
+		{
+			name: "complete",
+			cmd: CompleteCareItem{
+				CommandID:   "cmd-complete",
+				ItemID:      "item-cmd-schedule",
+				CompletedAt: "2026-03-01T09:30:00Z",
+			},
+			expected: "CareItemCompleted",
+		},
+		{
+			name: "cancel",
+			cmd: CancelCareItem{
+				CommandID: "cmd-cancel",
+				ItemID:    "item-cmd-schedule",
+				Reason:    "vet closed",
+			},
+			expected: "CareItemCanceled",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(given)
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			events, err := aggregate.Decide(tc.cmd)
+			if err != nil {
+				t.Fatalf("decide: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+			if events[0].eventName() != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, events[0].eventName())
+			}
+		})
+	}
+}
+
+func TestCareItemLifecycleGivenUnknownOrClosedItemWhenDecideThenRejects(t *testing.T) {
+	given := append(registeredCatEvents(),
+		CareItemScheduled{
+			CommandID: "cmd-schedule-done",
+			ItemID:    "item-cmd-schedule-done",
+			Kind:      CareItemKindVaccine,
+			Title:     "Rabies booster",
+			DueAt:     "2026-03-01T09:00:00Z",
+		},
+		CareItemCompleted{
+			CommandID:   "cmd-complete",
+			ItemID:      "item-cmd-schedule-done",
+			CompletedAt: "2026-03-01T09:30:00Z",
+		},
+		CareItemScheduled{
+			CommandID: "cmd-schedule-canceled",
+			ItemID:    "item-cmd-schedule-canceled",
+			Kind:      CareItemKindVetAppointment,
+			Title:     "Dental checkup",
+			DueAt:     "2026-04-01T09:00:00Z",
+		},
+		CareItemCanceled{
+			CommandID: "cmd-cancel",
+			ItemID:    "item-cmd-schedule-canceled",
+		},
+	)
+	aggregate, err := LoadFrom(given)
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  Command
+		code string
+	}{
+		{
+			name: "complete unknown item",
+			cmd: CompleteCareItem{
+				CommandID:   "cmd-complete-unknown",
+				ItemID:      "item-missing",
+				CompletedAt: "2026-03-01T09:30:00Z",
+			},
+			code: CodeUnknownCareItem,
+		},
+		{
+			name: "reschedule completed item",
+			cmd: RescheduleCareItem{
+				CommandID: "cmd-reschedule-done",
+				ItemID:    "item-cmd-schedule-done",
+				NewDueAt:  "2026-03-08T09:00:00Z",
+			},
+			code: CodeCareItemCompleted,
+		},
+		{
+			name: "complete completed item",
+			cmd: CompleteCareItem{
+				CommandID:   "cmd-complete-again",
+				ItemID:      "item-cmd-schedule-done",
+				CompletedAt: "2026-03-02T09:30:00Z",
+			},
+			code: CodeCareItemCompleted,
+		},
+		{
+			name: "cancel canceled item",
+			cmd: CancelCareItem{
+				CommandID: "cmd-cancel-again",
+				ItemID:    "item-cmd-schedule-canceled",
+			},
+			code: CodeCareItemCanceled,
+		},
+		{
+			name: "complete canceled item",
+			cmd: CompleteCareItem{
+				CommandID:   "cmd-complete-canceled",
+				ItemID:      "item-cmd-schedule-canceled",
+				CompletedAt: "2026-04-01T09:30:00Z",
+			},
+			code: CodeCareItemCanceled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestCareItemDatesGivenReferenceTimeWhenOutsideDateWindowThenRejectsImplausibleDate(t *testing.T) {
+	reference := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	given := append(registeredCatEvents(), CareItemScheduled{
+		CommandID: "cmd-schedule",
+		ItemID:    "item-cmd-schedule",
+		Kind:      CareItemKindVaccine,
+		Title:     "Rabies booster",
+		DueAt:     "2026-03-01T09:00:00Z",
+	})
+	aggregate, err := LoadFrom(given, WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		cmd   Command
+		field string
+	}{
+		{name: "due before the date window", cmd: ScheduleCareItem{CommandID: "cmd-2", Kind: CareItemKindOther, Title: "Brush", DueAt: "1900-01-01T00:00:00Z"}, field: "due_at"},
+		{name: "new due before the date window", cmd: RescheduleCareItem{CommandID: "cmd-2", ItemID: "item-cmd-schedule", NewDueAt: "1900-01-01T00:00:00Z"}, field: "new_due_at"},
+		{name: "completed in the future", cmd: CompleteCareItem{CommandID: "cmd-2", ItemID: "item-cmd-schedule", CompletedAt: "2999-01-01T00:00:00Z"}, field: "completed_at"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			rejection, ok := err.(Rejection)
+			if !ok || rejection.Code != CodeImplausibleDate || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %v", CodeImplausibleDate, tc.field, err)
+			}
+		})
+	}
+
+	if _, err := aggregate.Decide(ScheduleCareItem{CommandID: "cmd-3", Kind: CareItemKindOther, Title: "Annual check-up", DueAt: "2027-03-01T09:00:00Z"}); err != nil {
+		t.Fatalf("expected a due date next year to be accepted, got %v", err)
+	}
+}
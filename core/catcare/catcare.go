@@ -1,22 +1,89 @@
 package catcare
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 const (
 	MinWeightGrams = 100
 	MaxWeightGrams = 30000
 )
 
+// MinBodyConditionScore and MaxBodyConditionScore bound the 9-point body
+// condition scale (1 emaciated, 5 ideal, 9 obese). A zero score means the
+// body condition was not assessed.
+const (
+	MinBodyConditionScore = 1
+	MaxBodyConditionScore = 9
+)
+
 const (
-	CodeAlreadyRegistered = "already_registered"
-	CodeNotRegistered     = "not_registered"
-	CodeDuplicateCommand  = "duplicate_command"
-	CodeInvalidCommand    = "invalid_command"
-	CodeInvalidWeight     = "invalid_weight"
-	CodeAbsurdWeight      = "absurd_weight"
-	CodeInvalidName       = "invalid_name"
-	CodeInvalidCommandID  = "invalid_command_id"
-	CodeInvalidDate       = "invalid_date"
+	CodeAlreadyRegistered         = "already_registered"
+	CodeNotRegistered             = "not_registered"
+	CodeCatArchived               = "cat_archived"
+	CodeCatDeceased               = "cat_deceased"
+	CodeInvalidCaretaker          = "invalid_caretaker"
+	CodeInvalidMicrochip          = "invalid_microchip"
+	CodeDuplicateMicrochip        = "duplicate_microchip"
+	CodeMicrochipInUse            = "microchip_in_use"
+	CodeDuplicateCommand          = "duplicate_command"
+	CodeInvalidCommand            = "invalid_command"
+	CodeInvalidWeight             = "invalid_weight"
+	CodeAbsurdWeight              = "absurd_weight"
+	CodeInvalidWeightUnit         = "invalid_weight_unit"
+	CodeLossyWeight               = "lossy_weight"
+	CodeInvalidBodyConditionScore = "invalid_body_condition_score"
+	CodeInvalidIdealWeightRange   = "invalid_ideal_weight_range"
+	CodeInvalidEntryID            = "invalid_entry_id"
+	CodeUnknownWeightEntry        = "unknown_weight_entry"
+	CodeWeightEntryRetracted      = "weight_entry_retracted"
+	CodeUnchangedWeightEntry      = "unchanged_weight_entry"
+	CodeInvalidName               = "invalid_name"
+	CodeUnchangedName             = "unchanged_name"
+	CodeInvalidCommandID          = "invalid_command_id"
+	CodeTextTooLong               = "text_too_long"
+	CodeInvalidCharacters         = "invalid_characters"
+	CodeInvalidDate               = "invalid_date"
+	CodeImplausibleDate           = "implausible_date"
+	CodeInvalidKind               = "invalid_kind"
+	CodeInvalidVaccineType        = "invalid_vaccine_type"
+	CodeInvalidLotNumber          = "invalid_lot_number"
+	CodeInvalidDrugName           = "invalid_drug_name"
+	CodeInvalidDose               = "invalid_dose"
+	CodeInvalidRoute              = "invalid_route"
+	CodeInvalidFrequency          = "invalid_frequency"
+	CodeInvalidPrescriptionID     = "invalid_prescription_id"
+	CodeUnknownPrescription       = "unknown_prescription"
+	CodePrescriptionInactive      = "prescription_inactive"
+	CodeDoseTooSoon               = "dose_too_soon"
+	CodeInvalidFoodBrand          = "invalid_food_brand"
+	CodeInvalidFoodAmount         = "invalid_food_amount"
+	CodeAbsurdFoodAmount          = "absurd_food_amount"
+	CodeInvalidMealCount          = "invalid_meal_count"
+	CodeInvalidTitle              = "invalid_title"
+	CodeInvalidItemID             = "invalid_item_id"
+	CodeUnknownCareItem           = "unknown_care_item"
+	CodeCareItemCompleted         = "care_item_completed"
+	CodeCareItemCanceled          = "care_item_canceled"
+	CodeInvalidClinic             = "invalid_clinic"
+	CodeInvalidVetName            = "invalid_vet_name"
+	CodeInvalidReason             = "invalid_reason"
+	CodeInvalidVisitID            = "invalid_visit_id"
+	CodeUnknownVetVisit           = "unknown_vet_visit"
+	CodeNotVetAppointment         = "not_vet_appointment"
+	CodeInvalidAttachmentID       = "invalid_attachment_id"
+	CodeUnknownAttachment         = "unknown_attachment"
+	CodeInvalidRecurrence         = "invalid_recurrence"
+	CodeInvalidSummary            = "invalid_summary"
+	CodeInvalidSeverity           = "invalid_severity"
+	CodeInvalidAnomalyID          = "invalid_anomaly_id"
+	CodeUnknownAnomaly            = "unknown_anomaly"
+	CodeAnomalyResolved           = "anomaly_resolved"
+	CodeInvalidPlanID             = "invalid_plan_id"
+	CodeUnknownTreatmentPlan      = "unknown_treatment_plan"
+	CodeTreatmentPlanEnded        = "treatment_plan_ended"
+	CodeEmptyPatch                = "empty_patch"
 )
 
 type Rejection struct {
@@ -42,6 +109,11 @@ type Event interface {
 	commandID() string
 }
 
+// CommandID returns the idempotency key of a command.
+func CommandID(command Command) string {
+	return command.commandID()
+}
+
 type RegisterCat struct {
 	CommandID string
 	Name      string
@@ -51,16 +123,30 @@ type RegisterCat struct {
 func (c RegisterCat) commandName() string { return "RegisterCat" }
 func (c RegisterCat) commandID() string   { return c.CommandID }
 
+// LogWeight records a measurement either as whole Grams or as Amount (a
+// decimal such as "9.5") in Unit g, kg or lb. BodyConditionScore is optional
+// (0 when not assessed) on the 1-9 scale.
 type LogWeight struct {
-	CommandID string
-	At        string
-	Grams     int
-	Notes     string
+	CommandID          string
+	At                 string
+	Grams              int
+	Amount             string
+	Unit               string
+	BodyConditionScore int
+	Notes              string
 }
 
 func (c LogWeight) commandName() string { return "LogWeight" }
 func (c LogWeight) commandID() string   { return c.CommandID }
 
+type RenameCat struct {
+	CommandID string
+	NewName   string
+}
+
+func (c RenameCat) commandName() string { return "RenameCat" }
+func (c RenameCat) commandID() string   { return c.CommandID }
+
 type CatRegistered struct {
 	CommandID string
 	CatID     string
@@ -71,12 +157,26 @@ type CatRegistered struct {
 func (e CatRegistered) eventName() string { return "CatRegistered" }
 func (e CatRegistered) commandID() string { return e.CommandID }
 
-type WeightLogged struct {
+type CatRenamed struct {
 	CommandID string
-	EntryID   string
-	At        string
-	Grams     int
-	Notes     string
+	CatID     string
+	NewName   string
+}
+
+func (e CatRenamed) eventName() string { return "CatRenamed" }
+func (e CatRenamed) commandID() string { return e.CommandID }
+
+// WeightLogged always carries Grams; EnteredAmount and EnteredUnit keep what
+// was typed when another unit was used.
+type WeightLogged struct {
+	CommandID          string
+	EntryID            string
+	At                 string
+	Grams              int
+	EnteredAmount      string
+	EnteredUnit        string
+	BodyConditionScore int
+	Notes              string
 }
 
 func (e WeightLogged) eventName() string { return "WeightLogged" }
@@ -85,20 +185,55 @@ func (e WeightLogged) commandID() string { return e.CommandID }
 type CatCare struct {
 	CatID               string
 	Name                string
+	NameHistory         []string
 	BirthDate           string
 	Registered          bool
+	Status              string
+	CaretakerRef        string
+	Microchips          []string
 	WeightEntries       []WeightLogged
+	IdealWeightRange    *IdealWeightRange
+	CareItems           map[string]CareItem
+	Anomalies           map[string]Anomaly
+	TreatmentPlans      map[string]TreatmentPlan
+	Vaccinations        []VaccinationRecorded
+	VetVisits           map[string]VetVisitRecorded
+	Prescriptions       map[string]Prescription
+	DietPlan            *DietPlan
+	Meals               []MealLogged
+	retractedEntryIDs   map[string]struct{}
 	processedCommandIDs map[string]struct{}
+	referenceTime       time.Time
+	dateWindow          DateWindow
+	weightChangePolicy  WeightChangePolicy
+	doseSpacingPolicy   DoseSpacingPolicy
+	textPolicy          TextPolicy
+	knownAttachments    map[string]struct{}
+	allRejections       bool
 }
 
-func New() *CatCare {
-	return &CatCare{
+func New(options ...Option) *CatCare {
+	aggregate := &CatCare{
+		CareItems:           map[string]CareItem{},
+		Anomalies:           map[string]Anomaly{},
+		TreatmentPlans:      map[string]TreatmentPlan{},
+		Prescriptions:       map[string]Prescription{},
+		VetVisits:           map[string]VetVisitRecorded{},
+		retractedEntryIDs:   map[string]struct{}{},
 		processedCommandIDs: map[string]struct{}{},
+		dateWindow:          DefaultDateWindow(),
+		weightChangePolicy:  DefaultWeightChangePolicy(),
+		doseSpacingPolicy:   DefaultDoseSpacingPolicy(),
+		textPolicy:          DefaultTextPolicy(),
+	}
+	for _, option := range options {
+		option(aggregate)
 	}
+	return aggregate
 }
 
-func LoadFrom(events []Event) (*CatCare, error) {
-	aggregate := New()
+func LoadFrom(events []Event, options ...Option) (*CatCare, error) {
+	aggregate := New(options...)
 	for _, event := range events {
 		if err := aggregate.Apply(event); err != nil {
 			return nil, err
@@ -117,12 +252,61 @@ func (a *CatCare) Decide(command Command) ([]Event, error) {
 	if _, exists := a.processedCommandIDs[command.commandID()]; exists {
 		return nil, Rejection{Code: CodeDuplicateCommand, Message: "already applied", Field: "command_id"}
 	}
+	if err := a.checkLifecycle(command); err != nil {
+		return nil, err
+	}
 
 	switch cmd := command.(type) {
 	case RegisterCat:
 		return a.decideRegisterCat(cmd)
+	case RenameCat:
+		return a.decideRenameCat(cmd)
+	case MarkCatDeceased:
+		return a.decideMarkCatDeceased(cmd)
+	case ArchiveCat:
+		return a.decideArchiveCat(cmd)
+	case TransferCat:
+		return a.decideTransferCat(cmd)
+	case RegisterMicrochip:
+		return a.decideRegisterMicrochip(cmd)
 	case LogWeight:
 		return a.decideLogWeight(cmd)
+	case SetIdealWeightRange:
+		return a.decideSetIdealWeightRange(cmd)
+	case CorrectWeightEntry:
+		return a.decideCorrectWeightEntry(cmd)
+	case RetractWeightEntry:
+		return a.decideRetractWeightEntry(cmd)
+	case RecordVaccination:
+		return a.decideRecordVaccination(cmd)
+	case RecordVetVisit:
+		return a.decideRecordVetVisit(cmd)
+	case PrescribeMedication:
+		return a.decidePrescribeMedication(cmd)
+	case RecordDoseGiven:
+		return a.decideRecordDoseGiven(cmd)
+	case SetDietPlan:
+		return a.decideSetDietPlan(cmd)
+	case LogMeal:
+		return a.decideLogMeal(cmd)
+	case ScheduleCareItem:
+		return a.decideScheduleCareItem(cmd)
+	case RescheduleCareItem:
+		return a.decideRescheduleCareItem(cmd)
+	case CompleteCareItem:
+		return a.decideCompleteCareItem(cmd)
+	case CancelCareItem:
+		return a.decideCancelCareItem(cmd)
+	case ReportAnomaly:
+		return a.decideReportAnomaly(cmd)
+	case ResolveAnomaly:
+		return a.decideResolveAnomaly(cmd)
+	case StartTreatmentPlan:
+		return a.decideStartTreatmentPlan(cmd)
+	case UpdateTreatmentPlan:
+		return a.decideUpdateTreatmentPlan(cmd)
+	case EndTreatmentPlan:
+		return a.decideEndTreatmentPlan(cmd)
 	default:
 		return nil, Rejection{Code: CodeInvalidCommand, Message: "unknown command"}
 	}
@@ -133,37 +317,210 @@ func (a *CatCare) Apply(event Event) error {
 	case CatRegistered:
 		a.CatID = ev.CatID
 		a.Name = ev.Name
+		a.NameHistory = append(a.NameHistory, ev.Name)
 		a.BirthDate = ev.BirthDate
 		a.Registered = true
-		if ev.CommandID != "" {
-			a.processedCommandIDs[ev.CommandID] = struct{}{}
-		}
-		return nil
+		a.Status = LifecycleActive
+	case CatRenamed:
+		a.Name = ev.NewName
+		a.NameHistory = append(a.NameHistory, ev.NewName)
+	case CatMarkedDeceased:
+		a.Status = LifecycleDeceased
+	case CatArchived:
+		a.Status = LifecycleArchived
+	case CatTransferred:
+		a.CaretakerRef = ev.CaretakerRef
+	case MicrochipRegistered:
+		a.Microchips = append(a.Microchips, ev.ChipNumber)
 	case WeightLogged:
 		a.WeightEntries = append(a.WeightEntries, ev)
-		if ev.CommandID != "" {
-			a.processedCommandIDs[ev.CommandID] = struct{}{}
+	case IdealWeightRangeSet:
+		a.IdealWeightRange = &IdealWeightRange{
+			MinGrams: ev.MinGrams,
+			MaxGrams: ev.MaxGrams,
+			VetName:  ev.VetName,
+			VisitID:  ev.VisitID,
+		}
+	case WeightEntryCorrected:
+		if index := a.weightEntryIndex(ev.EntryID); index >= 0 {
+			entry := a.WeightEntries[index]
+			entry.At = ev.At
+			entry.Grams = ev.Grams
+			entry.EnteredAmount = ev.EnteredAmount
+			entry.EnteredUnit = ev.EnteredUnit
+			entry.BodyConditionScore = ev.BodyConditionScore
+			entry.Notes = ev.Notes
+			a.WeightEntries[index] = entry
+		}
+	case WeightEntryRetracted:
+		if index := a.weightEntryIndex(ev.EntryID); index >= 0 {
+			a.WeightEntries = append(a.WeightEntries[:index:index], a.WeightEntries[index+1:]...)
+		}
+		a.retractedEntryIDs[ev.EntryID] = struct{}{}
+	case VaccinationRecorded:
+		a.Vaccinations = append(a.Vaccinations, ev)
+	case VetVisitRecorded:
+		a.VetVisits[ev.VisitID] = ev
+	case MedicationPrescribed:
+		a.Prescriptions[ev.PrescriptionID] = Prescription{
+			PrescriptionID: ev.PrescriptionID,
+			DrugName:       ev.DrugName,
+			Dose:           ev.Dose,
+			Unit:           ev.Unit,
+			Route:          ev.Route,
+			IntervalHours:  ev.IntervalHours,
+			StartsAt:       ev.StartsAt,
+			EndsAt:         ev.EndsAt,
+		}
+	case DoseGiven:
+		prescription := a.Prescriptions[ev.PrescriptionID]
+		prescription.DosesGivenAt = append(prescription.DosesGivenAt, ev.GivenAt)
+		a.Prescriptions[ev.PrescriptionID] = prescription
+	case DietPlanSet:
+		a.DietPlan = &DietPlan{
+			PlanID:     ev.PlanID,
+			FoodBrand:  ev.FoodBrand,
+			DailyGrams: ev.DailyGrams,
+			MealCount:  ev.MealCount,
+			StartsOn:   ev.StartsOn,
+		}
+	case MealLogged:
+		a.Meals = append(a.Meals, ev)
+	case CareItemScheduled:
+		a.CareItems[ev.ItemID] = CareItem{
+			ItemID:            ev.ItemID,
+			Kind:              ev.Kind,
+			Title:             ev.Title,
+			DueAt:             ev.DueAt,
+			Notes:             ev.Notes,
+			Recurrence:        ev.Recurrence,
+			PlanID:            ev.PlanID,
+			Status:            CareItemStatusScheduled,
+			FollowUpOfVisitID: ev.FollowUpOfVisitID,
+		}
+	case CareItemRescheduled:
+		item := a.CareItems[ev.ItemID]
+		item.DueAt = ev.NewDueAt
+		a.CareItems[ev.ItemID] = item
+	case CareItemCompleted:
+		item := a.CareItems[ev.ItemID]
+		item.Status = CareItemStatusCompleted
+		item.CompletedAt = ev.CompletedAt
+		item.VisitID = ev.VisitID
+		if ev.Notes != "" {
+			item.Notes = ev.Notes
+		}
+		a.CareItems[ev.ItemID] = item
+	case CareItemCanceled:
+		item := a.CareItems[ev.ItemID]
+		item.Status = CareItemStatusCanceled
+		item.CancelReason = ev.Reason
+		a.CareItems[ev.ItemID] = item
+	case AnomalyReported:
+		a.Anomalies[ev.AnomalyID] = Anomaly{
+			AnomalyID:     ev.AnomalyID,
+			At:            ev.At,
+			Summary:       ev.Summary,
+			Severity:      ev.Severity,
+			Tags:          ev.Tags,
+			Notes:         ev.Notes,
+			AttachmentIDs: ev.AttachmentIDs,
+			WeightEntryID: ev.WeightEntryID,
 		}
-		return nil
+	case AnomalyResolved:
+		anomaly := a.Anomalies[ev.AnomalyID]
+		anomaly.Resolved = true
+		anomaly.ResolvedAt = ev.ResolvedAt
+		anomaly.ResolutionNotes = ev.Notes
+		a.Anomalies[ev.AnomalyID] = anomaly
+	case TreatmentPlanStarted:
+		a.TreatmentPlans[ev.PlanID] = TreatmentPlan{
+			PlanID:    ev.PlanID,
+			Title:     ev.Title,
+			StartedAt: ev.StartedAt,
+			Protocol:  ev.Protocol,
+			Notes:     ev.Notes,
+		}
+	case TreatmentPlanUpdated:
+		plan := a.TreatmentPlans[ev.PlanID]
+		if ev.Title != nil {
+			plan.Title = *ev.Title
+		}
+		if ev.Protocol != nil {
+			plan.Protocol = *ev.Protocol
+		}
+		if ev.Notes != nil {
+			plan.Notes = *ev.Notes
+		}
+		a.TreatmentPlans[ev.PlanID] = plan
+	case TreatmentPlanEnded:
+		plan := a.TreatmentPlans[ev.PlanID]
+		plan.Ended = true
+		plan.EndedAt = ev.EndedAt
+		plan.Outcome = ev.Outcome
+		plan.EndNotes = ev.Notes
+		a.TreatmentPlans[ev.PlanID] = plan
 	default:
 		return Rejection{Code: CodeInvalidCommand, Message: "unknown event"}
 	}
+
+	if event.commandID() != "" {
+		a.processedCommandIDs[event.commandID()] = struct{}{}
+	}
+	return nil
 }
 
 func (a *CatCare) decideRegisterCat(cmd RegisterCat) ([]Event, error) {
 	if a.Registered {
 		return nil, Rejection{Code: CodeAlreadyRegistered, Message: "cat already registered"}
 	}
-	if strings.TrimSpace(cmd.Name) == "" {
-		return nil, Rejection{Code: CodeInvalidName, Message: "must not be empty", Field: "name"}
+	v := a.validation()
+	name, err := a.requiredText("name", cmd.Name, CodeInvalidName)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	birthDate := strings.TrimSpace(cmd.BirthDate)
+	if birthDate != "" {
+		if _, err := a.plausibleCalendarDate("birth_date", birthDate); v.fail(err) {
+			return nil, v.err()
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
 	}
 
 	catID := mintID("cat", cmd.CommandID)
 	event := CatRegistered{
 		CommandID: cmd.CommandID,
 		CatID:     catID,
-		Name:      strings.TrimSpace(cmd.Name),
-		BirthDate: strings.TrimSpace(cmd.BirthDate),
+		Name:      name,
+		BirthDate: birthDate,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideRenameCat(cmd RenameCat) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	newName, err := a.requiredText("new_name", cmd.NewName, CodeInvalidName)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err == nil && newName == a.Name {
+		if v.fail(Rejection{Code: CodeUnchangedName, Message: "must differ from the current name", Field: "new_name"}) {
+			return nil, v.err()
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := CatRenamed{
+		CommandID: cmd.CommandID,
+		CatID:     a.CatID,
+		NewName:   newName,
 	}
 	return []Event{event}, nil
 }
@@ -172,22 +529,41 @@ func (a *CatCare) decideLogWeight(cmd LogWeight) ([]Event, error) {
 	if !a.Registered {
 		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
 	}
-	if strings.TrimSpace(cmd.At) == "" {
-		return nil, Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: "at"}
+	v := a.validation()
+	at := strings.TrimSpace(cmd.At)
+	measuredAt, err := a.plausibleTimestamp("at", at)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	grams, err := weightGrams(cmd.Grams, cmd.Amount, cmd.Unit)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if v.fail(checkBodyConditionScore(cmd.BodyConditionScore)) {
+		return nil, v.err()
 	}
-	if cmd.Grams <= 0 {
-		return nil, Rejection{Code: CodeInvalidWeight, Message: "must be positive", Field: "grams"}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
 	}
-	if cmd.Grams < MinWeightGrams || cmd.Grams > MaxWeightGrams {
-		return nil, Rejection{Code: CodeAbsurdWeight, Message: "outside allowed range", Field: "grams"}
+	if err := v.err(); err != nil {
+		return nil, err
 	}
 
 	event := WeightLogged{
-		CommandID: cmd.CommandID,
-		EntryID:   mintID("weight", cmd.CommandID),
-		At:        strings.TrimSpace(cmd.At),
-		Grams:     cmd.Grams,
-		Notes:     strings.TrimSpace(cmd.Notes),
+		CommandID:          cmd.CommandID,
+		EntryID:            mintID("weight", cmd.CommandID),
+		At:                 at,
+		Grams:              grams,
+		BodyConditionScore: cmd.BodyConditionScore,
+		Notes:              notes,
+	}
+	if cmd.Grams == 0 {
+		event.EnteredAmount = strings.TrimSpace(cmd.Amount)
+		event.EnteredUnit = strings.TrimSpace(cmd.Unit)
+	}
+	if anomaly, flagged := a.weightChangeAnomaly(event, measuredAt); flagged {
+		return []Event{event, anomaly}, nil
 	}
 	return []Event{event}, nil
 }
@@ -173,3 +173,78 @@ func TestDuplicateCommandIDGivenAppliedCommandWhenDecideThenRejects(t *testing.T
 		t.Fatalf("expected %q, got %q", CodeDuplicateCommand, rejection.Code)
 	}
 }
+
+func TestRenameCatGivenRegisteredCatWhenRenameThenEmitsCatRenamedAndKeepsHistory(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(RenameCat{
+		CommandID: "cmd-rename",
+		NewName:   " Mochi ",
+	})
+	if err != nil {
+		t.Fatalf("decide rename cat: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event, ok := events[0].(CatRenamed)
+	if !ok {
+		t.Fatalf("expected CatRenamed, got %T", events[0])
+	}
+	if event.CatID != "cat-cmd-register" || event.NewName != "Mochi" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if err := aggregate.Apply(event); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if aggregate.Name != "Mochi" {
+		t.Fatalf("expected name Mochi, got %q", aggregate.Name)
+	}
+	if len(aggregate.NameHistory) != 2 || aggregate.NameHistory[0] != "Miso" || aggregate.NameHistory[1] != "Mochi" {
+		t.Fatalf("unexpected name history %v", aggregate.NameHistory)
+	}
+}
+
+func TestRenameCatGivenRegisteredCatWhenRenameInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  RenameCat
+		code string
+	}{
+		{
+			name: "blank name",
+			cmd:  RenameCat{CommandID: "cmd-rename-blank", NewName: "   "},
+			code: CodeInvalidName,
+		},
+		{
+			name: "current name",
+			cmd:  RenameCat{CommandID: "cmd-rename-same", NewName: " Miso"},
+			code: CodeUnchangedName,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
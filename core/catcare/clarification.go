@@ -0,0 +1,21 @@
+package catcare
+
+// NeedsClarification is returned by Decide instead of a Rejection when an
+// input is ambiguous rather than wrong. The core never guesses: nothing is
+// decided, and the caller is expected to ask Question and resend the command
+// with one of the Candidates (or another unambiguous value) in Field.
+type NeedsClarification struct {
+	Field      string
+	Question   string
+	Candidates []string
+}
+
+func (c NeedsClarification) Error() string {
+	return "needs clarification: " + c.Field + " " + c.Question
+}
+
+// AsClarification reports whether a Decide error asks for clarification.
+func AsClarification(err error) (NeedsClarification, bool) {
+	clarification, ok := err.(NeedsClarification)
+	return clarification, ok
+}
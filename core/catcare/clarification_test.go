@@ -0,0 +1,151 @@
+package catcare
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecideGivenAmbiguousInputWhenDecideThenNeedsClarification(t *testing.T) {
+	reference := time.Date(2026, time.February, 14, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+	given := append(registeredCatEvents(), CareItemScheduled{
+		CommandID: "cmd-schedule",
+		ItemID:    "item-cmd-schedule",
+		Kind:      CareItemKindVetAppointment,
+		Title:     "Checkup",
+		DueAt:     "2026-03-01T09:00:00Z",
+	})
+	aggregate, err := LoadFrom(given, WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		command    Command
+		field      string
+		candidates []string
+	}{
+		{
+			name:       "ambiguous day and month in a calendar date",
+			command:    MarkCatDeceased{CommandID: "cmd-2", DiedOn: "02/03/2024"},
+			field:      "died_on",
+			candidates: []string{"2024-02-03", "2024-03-02"},
+		},
+		{
+			name:       "ambiguous day and month in a timestamp",
+			command:    LogWeight{CommandID: "cmd-2", At: "02/03/24 10:00", Grams: 4200},
+			field:      "at",
+			candidates: []string{"2024-02-03", "2024-03-02"},
+		},
+		{
+			name:       "same reading either way",
+			command:    MarkCatDeceased{CommandID: "cmd-2", DiedOn: "05.05.2024"},
+			field:      "died_on",
+			candidates: []string{"2024-05-05"},
+		},
+		{
+			name:       "timestamp without timezone",
+			command:    LogWeight{CommandID: "cmd-2", At: "2026-02-10 08:00", Grams: 4200},
+			field:      "at",
+			candidates: []string{"2026-02-10T08:00:00Z", "2026-02-10T08:00:00-03:00"},
+		},
+		{
+			name:       "grams that look like kilograms or pounds",
+			command:    LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4},
+			field:      "grams",
+			candidates: []string{"4 kg", "4 lb"},
+		},
+		{
+			name:       "grams that look like pounds",
+			command:    LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 50},
+			field:      "grams",
+			candidates: []string{"50 lb"},
+		},
+		{
+			name:       "fractional grams that look like kilograms or pounds",
+			command:    LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Amount: "4.2", Unit: UnitGrams},
+			field:      "unit",
+			candidates: []string{"4.2 kg", "4.2 lb"},
+		},
+		{
+			name:       "amount without a unit",
+			command:    LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Amount: "4.2"},
+			field:      "unit",
+			candidates: []string{"4.2 kg", "4.2 lb"},
+		},
+		{
+			name:       "amount without a unit that only fits kilograms",
+			command:    LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Amount: "4.125"},
+			field:      "unit",
+			candidates: []string{"4.125 kg"},
+		},
+		{
+			name: "nested follow-up due date",
+			command: RecordVetVisit{
+				CommandID: "cmd-2",
+				VisitedAt: "2026-02-10T08:00:00Z",
+				Clinic:    "Clinic",
+				Reason:    "Checkup",
+				FollowUps: []VetFollowUp{{Kind: CareItemKindVetAppointment, Title: "Recheck", DueAt: "2026-03-10T08:00:00"}},
+			},
+			field:      "follow_ups[0].due_at",
+			candidates: []string{"2026-03-10T08:00:00Z", "2026-03-10T08:00:00-03:00"},
+		},
+		{
+			name:       "care item due date without timezone",
+			command:    ScheduleCareItem{CommandID: "cmd-2", Kind: CareItemKindOther, Title: "Brush", DueAt: "2026-03-10 09:00"},
+			field:      "due_at",
+			candidates: []string{"2026-03-10T09:00:00Z", "2026-03-10T09:00:00-03:00"},
+		},
+		{
+			name:       "ambiguous new due date",
+			command:    RescheduleCareItem{CommandID: "cmd-2", ItemID: "item-cmd-schedule", NewDueAt: "02/03/2026"},
+			field:      "new_due_at",
+			candidates: []string{"2026-02-03", "2026-03-02"},
+		},
+		{
+			name:       "completion time without timezone",
+			command:    CompleteCareItem{CommandID: "cmd-2", ItemID: "item-cmd-schedule", CompletedAt: "2026-03-01 09:30"},
+			field:      "completed_at",
+			candidates: []string{"2026-03-01T09:30:00Z", "2026-03-01T09:30:00-03:00"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := aggregate.Decide(tc.command)
+			if len(events) != 0 {
+				t.Fatalf("expected no events, got %d", len(events))
+			}
+			clarification, ok := AsClarification(err)
+			if !ok {
+				t.Fatalf("expected NeedsClarification, got %v", err)
+			}
+			if clarification.Field != tc.field {
+				t.Fatalf("expected field %q, got %q", tc.field, clarification.Field)
+			}
+			if !reflect.DeepEqual(clarification.Candidates, tc.candidates) {
+				t.Fatalf("expected candidates %v, got %v", tc.candidates, clarification.Candidates)
+			}
+			if clarification.Question == "" {
+				t.Fatal("expected a question")
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenAllRejectionsWhenOneFieldAmbiguousThenNeedsClarification(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithAllRejections())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00", Grams: 0})
+	if _, ok := AsClarification(err); !ok {
+		t.Fatalf("expected NeedsClarification, got %v", err)
+	}
+	if _, ok := AsRejections(err); ok {
+		t.Fatal("clarification must not be reported as a rejection")
+	}
+}
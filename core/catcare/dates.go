@@ -0,0 +1,217 @@
+package catcare
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DateWindow bounds the dates the core accepts as plausible. Dates before
+// Earliest are rejected; dates more than MaxFuture after the reference time
+// are rejected. The reference time is an explicit input (see
+// WithReferenceTime); when it is zero only the lower bound is enforced.
+type DateWindow struct {
+	Earliest  time.Time
+	MaxFuture time.Duration
+}
+
+// DefaultDateWindow returns the window New uses when WithDateWindow is not
+// given.
+func DefaultDateWindow() DateWindow {
+	return DateWindow{
+		Earliest:  time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC),
+		MaxFuture: 24 * time.Hour,
+	}
+}
+
+// Option configures decision inputs that are not derived from events.
+type Option func(*CatCare)
+
+// WithReferenceTime sets the "now" used by sanity checks. Adapters pass the
+// authoritative time in; the core never reads the wall clock.
+func WithReferenceTime(reference time.Time) Option {
+	return func(a *CatCare) {
+		a.referenceTime = reference
+	}
+}
+
+func WithDateWindow(window DateWindow) Option {
+	return func(a *CatCare) {
+		a.dateWindow = window
+	}
+}
+
+// numericDayMonthDate matches forms like 02/03/2024 or 2.3.24, where the
+// order of day and month cannot be known without asking.
+var numericDayMonthDate = regexp.MustCompile(`^(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{2}|\d{4})(?:[ T].*)?$`)
+
+// localTimestamp matches date-times that carry no offset, such as
+// 2026-02-10T08:00 or 2026-02-10 08:00:00.
+var localTimestamp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})[T ](\d{2}:\d{2})(:\d{2}(?:\.\d+)?)?$`)
+
+// parseTimestamp parses value strictly as RFC3339. Numeric dates with an
+// unknown day/month order and date-times without an offset need
+// clarification.
+func (a *CatCare) parseTimestamp(field string, value string) (time.Time, error) {
+	if err := ambiguousDayMonth(field, value, "RFC3339"); err != nil {
+		return time.Time{}, err
+	}
+	if err := a.missingOffset(field, value); err != nil {
+		return time.Time{}, err
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, Rejection{Code: CodeInvalidDate, Message: "must be RFC3339", Field: field}
+	}
+	return parsed, nil
+}
+
+// parseCalendarDate parses value strictly as YYYY-MM-DD.
+func parseCalendarDate(field string, value string) (time.Time, error) {
+	if err := ambiguousDayMonth(field, value, "YYYY-MM-DD"); err != nil {
+		return time.Time{}, err
+	}
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, Rejection{Code: CodeInvalidDate, Message: "must be YYYY-MM-DD", Field: field}
+	}
+	return parsed, nil
+}
+
+// checkPlausible rejects dates outside the aggregate's date window.
+func (a *CatCare) checkPlausible(field string, value time.Time) error {
+	if err := a.checkEarliest(field, value); err != nil {
+		return err
+	}
+	if !a.referenceTime.IsZero() && value.After(a.referenceTime.Add(a.dateWindow.MaxFuture)) {
+		return Rejection{Code: CodeImplausibleDate, Message: "too far in the future", Field: field}
+	}
+	return nil
+}
+
+// checkEarliest rejects dates before the start of the date window. Dates
+// that may lie in the future, such as the start of a schedule, only get this
+// check.
+func (a *CatCare) checkEarliest(field string, value time.Time) error {
+	if value.Before(a.dateWindow.Earliest) {
+		return Rejection{Code: CodeImplausibleDate, Message: "before " + a.dateWindow.Earliest.Format(time.DateOnly), Field: field}
+	}
+	return nil
+}
+
+// ambiguousDayMonth asks which of the two readings of a numeric date was
+// meant, or returns nil when value is not such a date. Two-digit years are
+// read as 1980-2079.
+func ambiguousDayMonth(field string, value string, layout string) error {
+	match := numericDayMonthDate.FindStringSubmatch(value)
+	if match == nil {
+		return nil
+	}
+	first, _ := strconv.Atoi(match[1])
+	second, _ := strconv.Atoi(match[2])
+	if first < 1 || first > 12 || second < 1 || second > 12 {
+		return nil
+	}
+	year, _ := strconv.Atoi(match[3])
+	if len(match[3]) == 2 {
+		year += 2000
+		if year >= 2080 {
+			year -= 100
+		}
+	}
+
+	candidates := []string{
+		time.Date(year, time.Month(second), first, 0, 0, 0, 0, time.UTC).Format(time.DateOnly),
+		time.Date(year, time.Month(first), second, 0, 0, 0, 0, time.UTC).Format(time.DateOnly),
+	}
+	sort.Strings(candidates)
+	if candidates[0] == candidates[1] {
+		candidates = candidates[:1]
+	}
+	return NeedsClarification{
+		Field:      field,
+		Question:   "Which date is " + value + "? The day/month order is ambiguous; resend it as " + layout + ".",
+		Candidates: candidates,
+	}
+}
+
+// missingOffset asks for the timezone of a local date-time, or returns nil
+// when value is not one. Candidates are UTC and, when it differs, the offset
+// of the reference time.
+func (a *CatCare) missingOffset(field string, value string) error {
+	match := localTimestamp.FindStringSubmatch(value)
+	if match == nil {
+		return nil
+	}
+	seconds := match[3]
+	if seconds == "" {
+		seconds = ":00"
+	}
+	local := match[1] + "T" + match[2] + seconds
+	if _, err := time.Parse("2006-01-02T15:04:05.999999999", local); err != nil {
+		return nil
+	}
+
+	candidates := []string{local + "Z"}
+	if !a.referenceTime.IsZero() {
+		if offset := a.referenceTime.Format("Z07:00"); offset != "Z" {
+			candidates = append(candidates, local+offset)
+		}
+	}
+	return NeedsClarification{
+		Field:      field,
+		Question:   "Which timezone is " + value + " in? Resend it as RFC3339 with an offset.",
+		Candidates: candidates,
+	}
+}
+
+// plausibleTimestamp requires value, parses it as RFC3339 and checks it
+// against the date window.
+func (a *CatCare) plausibleTimestamp(field string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: field}
+	}
+	parsed, err := a.parseTimestamp(field, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed, a.checkPlausible(field, parsed)
+}
+
+// plausibleCalendarDate is plausibleTimestamp for YYYY-MM-DD values.
+func (a *CatCare) plausibleCalendarDate(field string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: field}
+	}
+	parsed, err := parseCalendarDate(field, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed, a.checkPlausible(field, parsed)
+}
+
+// scheduledTimestamp is plausibleTimestamp without the upper bound, for
+// values that are allowed to lie in the future.
+func (a *CatCare) scheduledTimestamp(field string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: field}
+	}
+	parsed, err := a.parseTimestamp(field, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed, a.checkEarliest(field, parsed)
+}
+
+// scheduledCalendarDate is scheduledTimestamp for YYYY-MM-DD values.
+func (a *CatCare) scheduledCalendarDate(field string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: field}
+	}
+	parsed, err := parseCalendarDate(field, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed, a.checkEarliest(field, parsed)
+}
@@ -0,0 +1,115 @@
+package catcare
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegisterCatGivenEmptyStreamWhenBirthDateInvalidThenRejects(t *testing.T) {
+	reference := time.Date(2026, time.February, 14, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		birthDate string
+		code      string
+	}{
+		{name: "not a calendar date", birthDate: "2024-02-30", code: CodeInvalidDate},
+		{name: "timestamp instead of date", birthDate: "2024-02-03T00:00:00Z", code: CodeInvalidDate},
+		{name: "non-iso order", birthDate: "25/03/2024", code: CodeInvalidDate},
+		{name: "before 1980", birthDate: "1979-12-31", code: CodeImplausibleDate},
+		{name: "more than a day in the future", birthDate: "2026-02-16", code: CodeImplausibleDate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(nil, WithReferenceTime(reference))
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			_, err = aggregate.Decide(RegisterCat{
+				CommandID: "cmd-1",
+				Name:      "Miso",
+				BirthDate: tc.birthDate,
+			})
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+			if rejection.Field != "birth_date" {
+				t.Fatalf("expected field birth_date, got %q", rejection.Field)
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenRegisteredCatWhenAtInvalidThenRejects(t *testing.T) {
+	reference := time.Date(2026, time.February, 14, 12, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(registeredCatEvents(), WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		at   string
+		code string
+	}{
+		{name: "date only", at: "2026-02-14", code: CodeInvalidDate},
+		{name: "free text", at: "yesterday", code: CodeInvalidDate},
+		{name: "before 1980", at: "1979-06-01T10:00:00Z", code: CodeImplausibleDate},
+		{name: "more than a day in the future", at: "2026-02-15T12:00:01Z", code: CodeImplausibleDate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(LogWeight{
+				CommandID: "cmd-weight",
+				At:        tc.at,
+				Grams:     4200,
+			})
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenCustomDateWindowWhenAtWithinWindowThenAccepts(t *testing.T) {
+	reference := time.Date(2026, time.February, 14, 12, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(registeredCatEvents(),
+		WithReferenceTime(reference),
+		WithDateWindow(DateWindow{
+			Earliest:  time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			MaxFuture: 72 * time.Hour,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(LogWeight{
+		CommandID: "cmd-weight",
+		At:        "2026-02-16T12:00:00+09:00",
+		Grams:     4200,
+	})
+	if err != nil {
+		t.Fatalf("decide log weight: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+}
@@ -0,0 +1,157 @@
+package catcare
+
+import "strings"
+
+const (
+	MinDailyFoodGrams = 5
+	MaxDailyFoodGrams = 1000
+	MinMealCount      = 1
+	MaxMealCount      = 12
+	MaxMealGrams      = MaxDailyFoodGrams
+)
+
+// DietPlan is the feeding plan in effect since StartsOn (YYYY-MM-DD). Setting
+// a new plan replaces the previous one.
+type DietPlan struct {
+	PlanID     string
+	FoodBrand  string
+	DailyGrams int
+	MealCount  int
+	StartsOn   string
+}
+
+type SetDietPlan struct {
+	CommandID  string
+	FoodBrand  string
+	DailyGrams int
+	MealCount  int
+	StartsOn   string
+}
+
+func (c SetDietPlan) commandName() string { return "SetDietPlan" }
+func (c SetDietPlan) commandID() string   { return c.CommandID }
+
+// LogMeal records one meal. GramsEaten may be zero (refused meal) but never
+// more than GramsOffered.
+type LogMeal struct {
+	CommandID    string
+	At           string
+	GramsOffered int
+	GramsEaten   int
+	Notes        string
+}
+
+func (c LogMeal) commandName() string { return "LogMeal" }
+func (c LogMeal) commandID() string   { return c.CommandID }
+
+type DietPlanSet struct {
+	CommandID  string
+	PlanID     string
+	FoodBrand  string
+	DailyGrams int
+	MealCount  int
+	StartsOn   string
+}
+
+func (e DietPlanSet) eventName() string { return "DietPlanSet" }
+func (e DietPlanSet) commandID() string { return e.CommandID }
+
+type MealLogged struct {
+	CommandID    string
+	MealID       string
+	At           string
+	GramsOffered int
+	GramsEaten   int
+	Notes        string
+}
+
+func (e MealLogged) eventName() string { return "MealLogged" }
+func (e MealLogged) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideSetDietPlan(cmd SetDietPlan) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	foodBrand, err := a.requiredText("food_brand", cmd.FoodBrand, CodeInvalidFoodBrand)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if v.fail(validateFoodGrams("daily_grams", cmd.DailyGrams, MinDailyFoodGrams, MaxDailyFoodGrams)) {
+		return nil, v.err()
+	}
+	if cmd.MealCount < MinMealCount || cmd.MealCount > MaxMealCount {
+		if v.fail(Rejection{Code: CodeInvalidMealCount, Message: "outside allowed range", Field: "meal_count"}) {
+			return nil, v.err()
+		}
+	}
+	startsOn := strings.TrimSpace(cmd.StartsOn)
+	if _, err := a.scheduledCalendarDate("starts_on", startsOn); v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := DietPlanSet{
+		CommandID:  cmd.CommandID,
+		PlanID:     mintID("diet", cmd.CommandID),
+		FoodBrand:  foodBrand,
+		DailyGrams: cmd.DailyGrams,
+		MealCount:  cmd.MealCount,
+		StartsOn:   startsOn,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideLogMeal(cmd LogMeal) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	at := strings.TrimSpace(cmd.At)
+	if _, err := a.plausibleTimestamp("at", at); v.fail(err) {
+		return nil, v.err()
+	}
+	if v.fail(validateFoodGrams("grams_offered", cmd.GramsOffered, 1, MaxMealGrams)) {
+		return nil, v.err()
+	}
+	if cmd.GramsEaten < 0 {
+		if v.fail(Rejection{Code: CodeInvalidFoodAmount, Message: "must not be negative", Field: "grams_eaten"}) {
+			return nil, v.err()
+		}
+	} else if cmd.GramsEaten > cmd.GramsOffered {
+		if v.fail(Rejection{Code: CodeInvalidFoodAmount, Message: "must not exceed grams_offered", Field: "grams_eaten"}) {
+			return nil, v.err()
+		}
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := MealLogged{
+		CommandID:    cmd.CommandID,
+		MealID:       mintID("meal", cmd.CommandID),
+		At:           at,
+		GramsOffered: cmd.GramsOffered,
+		GramsEaten:   cmd.GramsEaten,
+		Notes:        notes,
+	}
+	return []Event{event}, nil
+}
+
+// validateFoodGrams mirrors validateGrams: non-positive amounts are invalid,
+// amounts outside [min, max] are absurd.
+func validateFoodGrams(field string, grams int, min int, max int) error {
+	if grams <= 0 {
+		return Rejection{Code: CodeInvalidFoodAmount, Message: "must be positive", Field: field}
+	}
+	if grams < min || grams > max {
+		return Rejection{Code: CodeAbsurdFoodAmount, Message: "outside allowed range", Field: field}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package catcare
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetDietPlanGivenRegisteredCatWhenSetThenEmitsDietPlanSet(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(SetDietPlan{
+		CommandID:  "cmd-diet",
+		FoodBrand:  " Acme Renal ",
+		DailyGrams: 60,
+		MealCount:  2,
+		StartsOn:   "2026-02-10",
+	})
+	if err != nil {
+		t.Fatalf("decide set diet plan: %v", err)
+	}
+	event, ok := events[0].(DietPlanSet)
+	if !ok {
+		t.Fatalf("expected DietPlanSet, got %T", events[0])
+	}
+	if event.PlanID != "diet-cmd-diet" || event.FoodBrand != "Acme Renal" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if err := aggregate.Apply(event); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if aggregate.DietPlan == nil || aggregate.DietPlan.DailyGrams != 60 {
+		t.Fatalf("expected current diet plan, got %+v", aggregate.DietPlan)
+	}
+}
+
+func TestSetDietPlanGivenReferenceTimeWhenPlanStartsNextWeekThenAccepts(t *testing.T) {
+	reference := time.Date(2026, time.February, 10, 9, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(registeredCatEvents(), WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(SetDietPlan{
+		CommandID:  "cmd-diet",
+		FoodBrand:  "Acme Renal",
+		DailyGrams: 60,
+		MealCount:  2,
+		StartsOn:   "2026-02-17",
+	})
+	if err != nil {
+		t.Fatalf("expected a future start to be accepted, got %v", err)
+	}
+}
+
+func TestSetDietPlanGivenRegisteredCatWhenOutOfBoundsThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	valid := SetDietPlan{CommandID: "cmd-diet", FoodBrand: "Acme", DailyGrams: 60, MealCount: 2, StartsOn: "2026-02-10"}
+	cases := []struct {
+		name   string
+		mutate func(*SetDietPlan)
+		code   string
+	}{
+		{name: "blank brand", mutate: func(c *SetDietPlan) { c.FoodBrand = " " }, code: CodeInvalidFoodBrand},
+		{name: "zero grams", mutate: func(c *SetDietPlan) { c.DailyGrams = 0 }, code: CodeInvalidFoodAmount},
+		{name: "absurd grams", mutate: func(c *SetDietPlan) { c.DailyGrams = MaxDailyFoodGrams + 1 }, code: CodeAbsurdFoodAmount},
+		{name: "no meals", mutate: func(c *SetDietPlan) { c.MealCount = 0 }, code: CodeInvalidMealCount},
+		{name: "too many meals", mutate: func(c *SetDietPlan) { c.MealCount = MaxMealCount + 1 }, code: CodeInvalidMealCount},
+		{name: "bad start", mutate: func(c *SetDietPlan) { c.StartsOn = "2026-02-30" }, code: CodeInvalidDate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := valid
+			tc.mutate(&cmd)
+
+			_, err := aggregate.Decide(cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestLogMealGivenRegisteredCatWhenLogThenEmitsMealLogged(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(LogMeal{CommandID: "cmd-meal", At: "2026-02-10T08:00:00Z", GramsOffered: 30, GramsEaten: 0, Notes: "refused"})
+	if err != nil {
+		t.Fatalf("decide log meal: %v", err)
+	}
+	event, ok := events[0].(MealLogged)
+	if !ok {
+		t.Fatalf("expected MealLogged, got %T", events[0])
+	}
+	if event.MealID != "meal-cmd-meal" || event.GramsEaten != 0 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
+
+func TestLogMealGivenRegisteredCatWhenAmountsInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		offered int
+		eaten   int
+		code    string
+	}{
+		{name: "nothing offered", offered: 0, eaten: 0, code: CodeInvalidFoodAmount},
+		{name: "absurd offer", offered: MaxMealGrams + 1, eaten: 10, code: CodeAbsurdFoodAmount},
+		{name: "negative eaten", offered: 30, eaten: -1, code: CodeInvalidFoodAmount},
+		{name: "ate more than offered", offered: 30, eaten: 31, code: CodeInvalidFoodAmount},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(LogMeal{CommandID: "cmd-meal", At: "2026-02-10T08:00:00Z", GramsOffered: tc.offered, GramsEaten: tc.eaten})
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package catcare
+
+// EventName returns the stable name an event is persisted under.
+func EventName(event Event) string {
+	return event.eventName()
+}
+
+// EventTypes returns a zero value of every event the aggregate emits, so
+// adapters can register them with their codecs.
+func EventTypes() []Event {
+	return []Event{
+		CatRegistered{},
+		CatRenamed{},
+		CatMarkedDeceased{},
+		CatArchived{},
+		CatTransferred{},
+		MicrochipRegistered{},
+		WeightLogged{},
+		WeightEntryCorrected{},
+		WeightEntryRetracted{},
+		IdealWeightRangeSet{},
+		CareItemScheduled{},
+		CareItemRescheduled{},
+		CareItemCompleted{},
+		CareItemCanceled{},
+		AnomalyReported{},
+		AnomalyResolved{},
+		TreatmentPlanStarted{},
+		TreatmentPlanUpdated{},
+		TreatmentPlanEnded{},
+		VaccinationRecorded{},
+		MedicationPrescribed{},
+		DoseGiven{},
+		DietPlanSet{},
+		MealLogged{},
+		VetVisitRecorded{},
+		AttachmentRegistered{},
+	}
+}
@@ -0,0 +1,104 @@
+package catcare
+
+import "strings"
+
+// IdealWeightRange is the target weight a vet set for the cat. The latest
+// IdealWeightRangeSet replaces any earlier range.
+type IdealWeightRange struct {
+	MinGrams int
+	MaxGrams int
+	VetName  string
+	VisitID  string
+}
+
+// SetIdealWeightRange is a vet command: VetName is required, and VisitID
+// optionally ties the range to a recorded visit.
+type SetIdealWeightRange struct {
+	CommandID string
+	MinGrams  int
+	MaxGrams  int
+	VetName   string
+	VisitID   string
+	Notes     string
+}
+
+func (c SetIdealWeightRange) commandName() string { return "SetIdealWeightRange" }
+func (c SetIdealWeightRange) commandID() string   { return c.CommandID }
+
+type IdealWeightRangeSet struct {
+	CommandID string
+	MinGrams  int
+	MaxGrams  int
+	VetName   string
+	VisitID   string
+	Notes     string
+}
+
+func (e IdealWeightRangeSet) eventName() string { return "IdealWeightRangeSet" }
+func (e IdealWeightRangeSet) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideSetIdealWeightRange(cmd SetIdealWeightRange) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	if !plausibleGrams(cmd.MinGrams) {
+		if v.fail(Rejection{Code: CodeAbsurdWeight, Message: "outside allowed range", Field: "min_grams"}) {
+			return nil, v.err()
+		}
+	}
+	if !plausibleGrams(cmd.MaxGrams) {
+		if v.fail(Rejection{Code: CodeAbsurdWeight, Message: "outside allowed range", Field: "max_grams"}) {
+			return nil, v.err()
+		}
+	}
+	if cmd.MinGrams >= cmd.MaxGrams {
+		if v.fail(Rejection{Code: CodeInvalidIdealWeightRange, Message: "must be above min_grams", Field: "max_grams"}) {
+			return nil, v.err()
+		}
+	}
+	vetName, err := a.requiredText("vet_name", cmd.VetName, CodeInvalidVetName)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	visitID := strings.TrimSpace(cmd.VisitID)
+	if visitID != "" {
+		if _, exists := a.VetVisits[visitID]; !exists {
+			if v.fail(Rejection{Code: CodeUnknownVetVisit, Message: "vet visit does not exist", Field: "visit_id"}) {
+				return nil, v.err()
+			}
+		}
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := IdealWeightRangeSet{
+		CommandID: cmd.CommandID,
+		MinGrams:  cmd.MinGrams,
+		MaxGrams:  cmd.MaxGrams,
+		VetName:   vetName,
+		VisitID:   visitID,
+		Notes:     notes,
+	}
+	return []Event{event}, nil
+}
+
+// IdealWeightStatus compares grams with the ideal range: "below", "within"
+// or "above", or "" when no range was set.
+func (a *CatCare) IdealWeightStatus(grams int) string {
+	switch {
+	case a.IdealWeightRange == nil:
+		return ""
+	case grams < a.IdealWeightRange.MinGrams:
+		return "below"
+	case grams > a.IdealWeightRange.MaxGrams:
+		return "above"
+	default:
+		return "within"
+	}
+}
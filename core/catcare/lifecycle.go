@@ -0,0 +1,174 @@
+package catcare
+
+import "strings"
+
+const (
+	LifecycleActive   = "active"
+	LifecycleDeceased = "deceased"
+	LifecycleArchived = "archived"
+)
+
+type MarkCatDeceased struct {
+	CommandID string
+	DiedOn    string
+	Notes     string
+}
+
+func (c MarkCatDeceased) commandName() string { return "MarkCatDeceased" }
+func (c MarkCatDeceased) commandID() string   { return c.CommandID }
+
+type ArchiveCat struct {
+	CommandID string
+	Reason    string
+}
+
+func (c ArchiveCat) commandName() string { return "ArchiveCat" }
+func (c ArchiveCat) commandID() string   { return c.CommandID }
+
+// TransferCat hands the record over to a new caretaker. CaretakerRef is an
+// opaque reference owned by the adapter (person, shelter, household, ...).
+type TransferCat struct {
+	CommandID     string
+	CaretakerRef  string
+	TransferredAt string
+	Notes         string
+}
+
+func (c TransferCat) commandName() string { return "TransferCat" }
+func (c TransferCat) commandID() string   { return c.CommandID }
+
+type CatMarkedDeceased struct {
+	CommandID string
+	CatID     string
+	DiedOn    string
+	Notes     string
+}
+
+func (e CatMarkedDeceased) eventName() string { return "CatMarkedDeceased" }
+func (e CatMarkedDeceased) commandID() string { return e.CommandID }
+
+type CatArchived struct {
+	CommandID string
+	CatID     string
+	Reason    string
+}
+
+func (e CatArchived) eventName() string { return "CatArchived" }
+func (e CatArchived) commandID() string { return e.CommandID }
+
+type CatTransferred struct {
+	CommandID            string
+	CatID                string
+	PreviousCaretakerRef string
+	CaretakerRef         string
+	TransferredAt        string
+	Notes                string
+}
+
+func (e CatTransferred) eventName() string { return "CatTransferred" }
+func (e CatTransferred) commandID() string { return e.CommandID }
+
+// checkLifecycle rejects mutations of cats that are no longer active. A
+// deceased cat can still be archived; an archived record is frozen.
+func (a *CatCare) checkLifecycle(command Command) error {
+	switch a.Status {
+	case LifecycleArchived:
+		return Rejection{Code: CodeCatArchived, Message: "cat record is archived"}
+	case LifecycleDeceased:
+		if _, archiving := command.(ArchiveCat); archiving {
+			return nil
+		}
+		return Rejection{Code: CodeCatDeceased, Message: "cat is deceased"}
+	}
+	return nil
+}
+
+func (a *CatCare) decideMarkCatDeceased(cmd MarkCatDeceased) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	diedOn := strings.TrimSpace(cmd.DiedOn)
+	_, err := a.plausibleCalendarDate("died_on", diedOn)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err == nil && a.BirthDate != "" && diedOn < a.BirthDate {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the birth date", Field: "died_on"}) {
+			return nil, v.err()
+		}
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := CatMarkedDeceased{
+		CommandID: cmd.CommandID,
+		CatID:     a.CatID,
+		DiedOn:    diedOn,
+		Notes:     notes,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideArchiveCat(cmd ArchiveCat) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	reason, err := a.text("reason", cmd.Reason)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := CatArchived{
+		CommandID: cmd.CommandID,
+		CatID:     a.CatID,
+		Reason:    reason,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideTransferCat(cmd TransferCat) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	caretakerRef, err := a.requiredText("caretaker_ref", cmd.CaretakerRef, CodeInvalidCaretaker)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err == nil && caretakerRef == a.CaretakerRef {
+		if v.fail(Rejection{Code: CodeInvalidCaretaker, Message: "must differ from the current caretaker", Field: "caretaker_ref"}) {
+			return nil, v.err()
+		}
+	}
+	transferredAt := strings.TrimSpace(cmd.TransferredAt)
+	if _, err := a.plausibleTimestamp("transferred_at", transferredAt); v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := CatTransferred{
+		CommandID:            cmd.CommandID,
+		CatID:                a.CatID,
+		PreviousCaretakerRef: a.CaretakerRef,
+		CaretakerRef:         caretakerRef,
+		TransferredAt:        transferredAt,
+		Notes:                notes,
+	}
+	return []Event{event}, nil
+}
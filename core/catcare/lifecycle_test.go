@@ -0,0 +1,144 @@
+package catcare
+
+import "testing"
+
+func TestLifecycleGivenActiveCatWhenLifecycleCommandThenEmitsEventAndUpdatesStatus(t *testing.T) {
+	cases := []struct {
+		name     string
+		cmd      Command
+		status   string
+		expected string
+	}{
+		{
+			name:     "mark deceased",
+			cmd:      MarkCatDeceased{CommandID: "cmd-deceased", DiedOn: "2026-02-10"},
+			status:   LifecycleDeceased,
+			expected: "CatMarkedDeceased",
+		},
+		{
+			name:     "archive",
+			cmd:      ArchiveCat{CommandID: "cmd-archive", Reason: "adopted out of network"},
+			status:   LifecycleArchived,
+			expected: "CatArchived",
+		},
+		{
+			name:     "transfer",
+			cmd:      TransferCat{CommandID: "cmd-transfer", CaretakerRef: "shelter-42", TransferredAt: "2026-02-10T15:00:00Z"},
+			status:   LifecycleActive,
+			expected: "CatTransferred",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(registeredCatEvents())
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			events, err := aggregate.Decide(tc.cmd)
+			if err != nil {
+				t.Fatalf("decide: %v", err)
+			}
+			if len(events) != 1 || events[0].eventName() != tc.expected {
+				t.Fatalf("expected single %s, got %v", tc.expected, events)
+			}
+			if err := aggregate.Apply(events[0]); err != nil {
+				t.Fatalf("apply event: %v", err)
+			}
+			if aggregate.Status != tc.status {
+				t.Fatalf("expected status %q, got %q", tc.status, aggregate.Status)
+			}
+		})
+	}
+}
+
+func TestLifecycleGivenInactiveCatWhenMutatingCommandThenRejects(t *testing.T) {
+	deceased := append(registeredCatEvents(), CatMarkedDeceased{
+		CommandID: "cmd-deceased",
+		CatID:     "cat-cmd-register",
+		DiedOn:    "2026-02-10",
+	})
+	archived := append(registeredCatEvents(), CatArchived{
+		CommandID: "cmd-archive",
+		CatID:     "cat-cmd-register",
+	})
+
+	cases := []struct {
+		name  string
+		given []Event
+		cmd   Command
+		code  string
+	}{
+		{
+			name:  "log weight for deceased cat",
+			given: deceased,
+			cmd:   LogWeight{CommandID: "cmd-weight", At: "2026-02-11T10:00:00Z", Grams: 4000},
+			code:  CodeCatDeceased,
+		},
+		{
+			name:  "schedule for deceased cat",
+			given: deceased,
+			cmd:   ScheduleCareItem{CommandID: "cmd-schedule", Kind: CareItemKindVaccine, Title: "Rabies", DueAt: "2026-03-01T09:00:00Z"},
+			code:  CodeCatDeceased,
+		},
+		{
+			name:  "log weight for archived cat",
+			given: archived,
+			cmd:   LogWeight{CommandID: "cmd-weight", At: "2026-02-11T10:00:00Z", Grams: 4000},
+			code:  CodeCatArchived,
+		},
+		{
+			name:  "transfer archived cat",
+			given: archived,
+			cmd:   TransferCat{CommandID: "cmd-transfer", CaretakerRef: "shelter-42", TransferredAt: "2026-02-10T15:00:00Z"},
+			code:  CodeCatArchived,
+		},
+		{
+			name:  "archive archived cat",
+			given: archived,
+			cmd:   ArchiveCat{CommandID: "cmd-archive-again"},
+			code:  CodeCatArchived,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(tc.given)
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			_, err = aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestArchiveCatGivenDeceasedCatWhenArchiveThenEmitsCatArchived(t *testing.T) {
+	aggregate, err := LoadFrom(append(registeredCatEvents(), CatMarkedDeceased{
+		CommandID: "cmd-deceased",
+		CatID:     "cat-cmd-register",
+		DiedOn:    "2026-02-10",
+	}))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(ArchiveCat{CommandID: "cmd-archive"})
+	if err != nil {
+		t.Fatalf("decide archive cat: %v", err)
+	}
+	if _, ok := events[0].(CatArchived); !ok {
+		t.Fatalf("expected CatArchived, got %T", events[0])
+	}
+}
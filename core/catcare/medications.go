@@ -0,0 +1,271 @@
+package catcare
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	RouteOral       = "ORAL"
+	RouteTopical    = "TOPICAL"
+	RouteInjection  = "INJECTION"
+	RouteOphthalmic = "OPHTHALMIC"
+	RouteOtic       = "OTIC"
+	RouteInhaled    = "INHALED"
+	RouteOther      = "OTHER"
+)
+
+const (
+	MinDoseIntervalHours = 1
+	MaxDoseIntervalHours = 24 * 31
+)
+
+// DoseSpacingPolicy decides how early a dose may follow another one. Doses
+// closer together than the dosing interval less EarlyTolerancePercent of it
+// are treated as a double dose.
+type DoseSpacingPolicy struct {
+	EarlyTolerancePercent int
+}
+
+// DefaultDoseSpacingPolicy returns the policy New uses when
+// WithDoseSpacingPolicy is not given.
+func DefaultDoseSpacingPolicy() DoseSpacingPolicy {
+	return DoseSpacingPolicy{
+		EarlyTolerancePercent: 10,
+	}
+}
+
+func WithDoseSpacingPolicy(policy DoseSpacingPolicy) Option {
+	return func(a *CatCare) {
+		a.doseSpacingPolicy = policy
+	}
+}
+
+type Prescription struct {
+	PrescriptionID string
+	DrugName       string
+	Dose           float64
+	Unit           string
+	Route          string
+	IntervalHours  int
+	StartsAt       string
+	EndsAt         string
+	DosesGivenAt   []string
+}
+
+// PrescribeMedication starts a dose schedule: one dose every IntervalHours
+// from StartsAt until EndsAt (RFC3339). An empty EndsAt is open-ended.
+type PrescribeMedication struct {
+	CommandID     string
+	DrugName      string
+	Dose          float64
+	Unit          string
+	Route         string
+	IntervalHours int
+	StartsAt      string
+	EndsAt        string
+}
+
+func (c PrescribeMedication) commandName() string { return "PrescribeMedication" }
+func (c PrescribeMedication) commandID() string   { return c.CommandID }
+
+type RecordDoseGiven struct {
+	CommandID      string
+	PrescriptionID string
+	GivenAt        string
+	Notes          string
+}
+
+func (c RecordDoseGiven) commandName() string { return "RecordDoseGiven" }
+func (c RecordDoseGiven) commandID() string   { return c.CommandID }
+
+type MedicationPrescribed struct {
+	CommandID      string
+	PrescriptionID string
+	DrugName       string
+	Dose           float64
+	Unit           string
+	Route          string
+	IntervalHours  int
+	StartsAt       string
+	EndsAt         string
+}
+
+func (e MedicationPrescribed) eventName() string { return "MedicationPrescribed" }
+func (e MedicationPrescribed) commandID() string { return e.CommandID }
+
+type DoseGiven struct {
+	CommandID      string
+	DoseID         string
+	PrescriptionID string
+	GivenAt        string
+	Notes          string
+}
+
+func (e DoseGiven) eventName() string { return "DoseGiven" }
+func (e DoseGiven) commandID() string { return e.CommandID }
+
+func (a *CatCare) decidePrescribeMedication(cmd PrescribeMedication) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	drugName, err := a.requiredText("drug_name", cmd.DrugName, CodeInvalidDrugName)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if !(cmd.Dose > 0) || math.IsInf(cmd.Dose, 0) {
+		if v.fail(Rejection{Code: CodeInvalidDose, Message: "must be a positive number", Field: "dose"}) {
+			return nil, v.err()
+		}
+	}
+	unit, err := a.requiredText("unit", cmd.Unit, CodeInvalidDose)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if !validRoute(cmd.Route) {
+		if v.fail(Rejection{Code: CodeInvalidRoute, Message: "must be one of ORAL, TOPICAL, INJECTION, OPHTHALMIC, OTIC, INHALED, OTHER", Field: "route"}) {
+			return nil, v.err()
+		}
+	}
+	if cmd.IntervalHours < MinDoseIntervalHours || cmd.IntervalHours > MaxDoseIntervalHours {
+		if v.fail(Rejection{Code: CodeInvalidFrequency, Message: "outside allowed range", Field: "interval_hours"}) {
+			return nil, v.err()
+		}
+	}
+
+	startsAt := strings.TrimSpace(cmd.StartsAt)
+	starts, startsErr := a.scheduledTimestamp("starts_at", startsAt)
+	if v.fail(startsErr) {
+		return nil, v.err()
+	}
+	endsAt := strings.TrimSpace(cmd.EndsAt)
+	if endsAt != "" {
+		ends, err := a.parseTimestamp("ends_at", endsAt)
+		if v.fail(err) {
+			return nil, v.err()
+		}
+		if err == nil && startsErr == nil && !ends.After(starts) {
+			if v.fail(Rejection{Code: CodeInvalidDate, Message: "must be after starts_at", Field: "ends_at"}) {
+				return nil, v.err()
+			}
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := MedicationPrescribed{
+		CommandID:      cmd.CommandID,
+		PrescriptionID: mintID("prescription", cmd.CommandID),
+		DrugName:       drugName,
+		Dose:           cmd.Dose,
+		Unit:           unit,
+		Route:          cmd.Route,
+		IntervalHours:  cmd.IntervalHours,
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+	}
+	return []Event{event}, nil
+}
+
+// decideRecordDoseGiven accepts a dose only inside the prescription window,
+// not after the reference ("as of") time, and not closer to another recorded
+// dose than the spacing policy allows.
+func (a *CatCare) decideRecordDoseGiven(cmd RecordDoseGiven) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	if strings.TrimSpace(cmd.PrescriptionID) == "" {
+		return nil, Rejection{Code: CodeInvalidPrescriptionID, Message: "must not be empty", Field: "prescription_id"}
+	}
+	prescription, exists := a.Prescriptions[cmd.PrescriptionID]
+	if !exists {
+		return nil, Rejection{Code: CodeUnknownPrescription, Message: "prescription does not exist", Field: "prescription_id"}
+	}
+
+	v := a.validation()
+	givenAt := strings.TrimSpace(cmd.GivenAt)
+	if v.fail(a.checkDoseTime(prescription, givenAt)) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := DoseGiven{
+		CommandID:      cmd.CommandID,
+		DoseID:         mintID("dose", cmd.CommandID),
+		PrescriptionID: prescription.PrescriptionID,
+		GivenAt:        givenAt,
+		Notes:          notes,
+	}
+	return []Event{event}, nil
+}
+
+// checkDoseTime reports the first problem with givenAt: it must be a past
+// RFC3339 time inside the prescription and keep its distance from the doses
+// already given.
+func (a *CatCare) checkDoseTime(prescription Prescription, givenAt string) error {
+	if givenAt == "" {
+		return Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: "given_at"}
+	}
+	given, err := a.parseTimestamp("given_at", givenAt)
+	if err != nil {
+		return err
+	}
+	if !a.referenceTime.IsZero() && given.After(a.referenceTime) {
+		return Rejection{Code: CodeInvalidDate, Message: "must not be in the future", Field: "given_at"}
+	}
+	if !prescription.activeAt(given) {
+		return Rejection{Code: CodePrescriptionInactive, Message: "no active prescription at that time", Field: "given_at"}
+	}
+
+	minSpacing := a.doseSpacingPolicy.minSpacing(prescription.IntervalHours)
+	for _, previousAt := range prescription.DosesGivenAt {
+		previous, err := time.Parse(time.RFC3339, previousAt)
+		if err != nil {
+			continue
+		}
+		spacing := given.Sub(previous)
+		if spacing < 0 {
+			spacing = -spacing
+		}
+		if spacing < minSpacing {
+			return Rejection{Code: CodeDoseTooSoon, Message: "another dose was given less than one dosing interval earlier or later", Field: "given_at"}
+		}
+	}
+	return nil
+}
+
+// minSpacing is the shortest gap allowed between two doses.
+func (p DoseSpacingPolicy) minSpacing(intervalHours int) time.Duration {
+	interval := time.Duration(intervalHours) * time.Hour
+	return interval - interval*time.Duration(p.EarlyTolerancePercent)/100
+}
+
+func (p Prescription) activeAt(at time.Time) bool {
+	starts, err := time.Parse(time.RFC3339, p.StartsAt)
+	if err != nil || at.Before(starts) {
+		return false
+	}
+	if p.EndsAt == "" {
+		return true
+	}
+	ends, err := time.Parse(time.RFC3339, p.EndsAt)
+	return err == nil && !at.After(ends)
+}
+
+func validRoute(route string) bool {
+	switch route {
+	case RouteOral, RouteTopical, RouteInjection, RouteOphthalmic, RouteOtic, RouteInhaled, RouteOther:
+		return true
+	default:
+		return false
+	}
+}
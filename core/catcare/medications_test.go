@@ -0,0 +1,232 @@
+package catcare
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func prescribedEvents() []Event {
+	return append(registeredCatEvents(),
+		MedicationPrescribed{
+			CommandID:      "cmd-prescribe",
+			PrescriptionID: "prescription-cmd-prescribe",
+			DrugName:       "Amoxicillin",
+			Dose:           50,
+			Unit:           "mg",
+			Route:          RouteOral,
+			IntervalHours:  12,
+			StartsAt:       "2026-02-01T08:00:00Z",
+			EndsAt:         "2026-02-14T20:00:00Z",
+		},
+		DoseGiven{
+			CommandID:      "cmd-dose-1",
+			DoseID:         "dose-cmd-dose-1",
+			PrescriptionID: "prescription-cmd-prescribe",
+			GivenAt:        "2026-02-10T08:00:00Z",
+		},
+	)
+}
+
+func TestPrescribeMedicationGivenRegisteredCatWhenPrescribeThenEmitsMedicationPrescribed(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(PrescribeMedication{
+		CommandID:     "cmd-prescribe",
+		DrugName:      "Amoxicillin",
+		Dose:          50,
+		Unit:          "mg",
+		Route:         RouteOral,
+		IntervalHours: 12,
+		StartsAt:      "2026-02-01T08:00:00Z",
+		EndsAt:        "2026-02-14T20:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("decide prescribe medication: %v", err)
+	}
+	event, ok := events[0].(MedicationPrescribed)
+	if !ok {
+		t.Fatalf("expected MedicationPrescribed, got %T", events[0])
+	}
+	if event.PrescriptionID != "prescription-cmd-prescribe" {
+		t.Fatalf("expected deterministic prescription id, got %q", event.PrescriptionID)
+	}
+}
+
+func TestPrescribeMedicationGivenRegisteredCatWhenPrescribeInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	valid := PrescribeMedication{
+		CommandID:     "cmd-prescribe",
+		DrugName:      "Amoxicillin",
+		Dose:          50,
+		Unit:          "mg",
+		Route:         RouteOral,
+		IntervalHours: 12,
+		StartsAt:      "2026-02-01T08:00:00Z",
+	}
+	cases := []struct {
+		name   string
+		mutate func(*PrescribeMedication)
+		code   string
+	}{
+		{name: "blank drug", mutate: func(c *PrescribeMedication) { c.DrugName = "" }, code: CodeInvalidDrugName},
+		{name: "zero dose", mutate: func(c *PrescribeMedication) { c.Dose = 0 }, code: CodeInvalidDose},
+		{name: "NaN dose", mutate: func(c *PrescribeMedication) { c.Dose = math.NaN() }, code: CodeInvalidDose},
+		{name: "infinite dose", mutate: func(c *PrescribeMedication) { c.Dose = math.Inf(1) }, code: CodeInvalidDose},
+		{name: "negative infinite dose", mutate: func(c *PrescribeMedication) { c.Dose = math.Inf(-1) }, code: CodeInvalidDose},
+		{name: "unknown route", mutate: func(c *PrescribeMedication) { c.Route = "NASAL" }, code: CodeInvalidRoute},
+		{name: "zero interval", mutate: func(c *PrescribeMedication) { c.IntervalHours = 0 }, code: CodeInvalidFrequency},
+		{name: "ends before start", mutate: func(c *PrescribeMedication) { c.EndsAt = "2026-01-31T08:00:00Z" }, code: CodeInvalidDate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := valid
+			tc.mutate(&cmd)
+
+			_, err := aggregate.Decide(cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestRecordDoseGivenGivenActivePrescriptionWhenDoseOnScheduleThenEmitsDoseGiven(t *testing.T) {
+	reference := time.Date(2026, time.February, 10, 21, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(prescribedEvents(), WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(RecordDoseGiven{
+		CommandID:      "cmd-dose-2",
+		PrescriptionID: "prescription-cmd-prescribe",
+		GivenAt:        "2026-02-10T20:15:00Z",
+	})
+	if err != nil {
+		t.Fatalf("decide record dose given: %v", err)
+	}
+	event, ok := events[0].(DoseGiven)
+	if !ok {
+		t.Fatalf("expected DoseGiven, got %T", events[0])
+	}
+	if event.DoseID != "dose-cmd-dose-2" {
+		t.Fatalf("expected deterministic dose id, got %q", event.DoseID)
+	}
+}
+
+func TestRecordDoseGivenGivenPrescriptionWhenDoseInvalidThenRejects(t *testing.T) {
+	reference := time.Date(2026, time.February, 10, 21, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(prescribedEvents(), WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name           string
+		prescriptionID string
+		givenAt        string
+		code           string
+	}{
+		{name: "double dose across shifts", prescriptionID: "prescription-cmd-prescribe", givenAt: "2026-02-10T13:30:00Z", code: CodeDoseTooSoon},
+		{name: "before prescription starts", prescriptionID: "prescription-cmd-prescribe", givenAt: "2026-01-31T08:00:00Z", code: CodePrescriptionInactive},
+		{name: "after reference time", prescriptionID: "prescription-cmd-prescribe", givenAt: "2026-02-11T08:00:00Z", code: CodeInvalidDate},
+		{name: "unknown prescription", prescriptionID: "prescription-missing", givenAt: "2026-02-10T20:00:00Z", code: CodeUnknownPrescription},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(RecordDoseGiven{
+				CommandID:      "cmd-dose-2",
+				PrescriptionID: tc.prescriptionID,
+				GivenAt:        tc.givenAt,
+			})
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestPrescribeMedicationGivenReferenceTimeWhenCourseStartsNextWeekThenAccepts(t *testing.T) {
+	reference := time.Date(2026, time.February, 10, 9, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(registeredCatEvents(), WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(PrescribeMedication{
+		CommandID:     "cmd-prescribe",
+		DrugName:      "Amoxicillin",
+		Dose:          50,
+		Unit:          "mg",
+		Route:         RouteOral,
+		IntervalHours: 12,
+		StartsAt:      "2026-02-17T08:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("expected a future start to be accepted, got %v", err)
+	}
+}
+
+func TestRecordDoseGivenGivenSpacingPolicyWhenDoseNearPreviousThenChecksToleranceBoundary(t *testing.T) {
+	reference := time.Date(2026, time.February, 10, 21, 0, 0, 0, time.UTC)
+	policy := DoseSpacingPolicy{EarlyTolerancePercent: 10}
+
+	cases := []struct {
+		name    string
+		givenAt string
+		code    string
+	}{
+		{name: "interval minus tolerance", givenAt: "2026-02-10T18:48:00Z"},
+		{name: "just inside tolerance", givenAt: "2026-02-10T18:47:00Z", code: CodeDoseTooSoon},
+		{name: "half the interval", givenAt: "2026-02-10T14:00:00Z", code: CodeDoseTooSoon},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(prescribedEvents(), WithReferenceTime(reference), WithDoseSpacingPolicy(policy))
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			_, err = aggregate.Decide(RecordDoseGiven{
+				CommandID:      "cmd-dose-2",
+				PrescriptionID: "prescription-cmd-prescribe",
+				GivenAt:        tc.givenAt,
+			})
+			if tc.code == "" {
+				if err != nil {
+					t.Fatalf("expected dose to be accepted, got %v", err)
+				}
+				return
+			}
+			rejection, ok := err.(Rejection)
+			if !ok || rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %v", tc.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package catcare
+
+import "strings"
+
+// MicrochipDigits is the length of an ISO 11784 (FDX-B) identification code:
+// a 3-digit country or manufacturer code followed by a 12-digit national id.
+const MicrochipDigits = 15
+
+// RegisterMicrochip records a chip implanted in the cat. ChipNumber may be
+// written in groups ("985 112 003 456 789"); it is stored as 15 digits.
+// ImplantedOn (YYYY-MM-DD) is optional.
+type RegisterMicrochip struct {
+	CommandID   string
+	ChipNumber  string
+	ImplantedOn string
+}
+
+func (c RegisterMicrochip) commandName() string { return "RegisterMicrochip" }
+func (c RegisterMicrochip) commandID() string   { return c.CommandID }
+
+// MicrochipRegistered claims ChipNumber for the cat. Uniqueness across cats
+// is not an aggregate invariant; adapters reserve the number before appending
+// (see CodeMicrochipInUse).
+type MicrochipRegistered struct {
+	CommandID   string
+	CatID       string
+	ChipNumber  string
+	ImplantedOn string
+}
+
+func (e MicrochipRegistered) eventName() string { return "MicrochipRegistered" }
+func (e MicrochipRegistered) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideRegisterMicrochip(cmd RegisterMicrochip) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	chipNumber, err := NormalizeMicrochip(cmd.ChipNumber)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	for _, registered := range a.Microchips {
+		if err == nil && registered == chipNumber {
+			if v.fail(Rejection{Code: CodeDuplicateMicrochip, Message: "already registered for this cat", Field: "chip_number"}) {
+				return nil, v.err()
+			}
+		}
+	}
+
+	implantedOn := strings.TrimSpace(cmd.ImplantedOn)
+	if implantedOn != "" {
+		_, err := a.plausibleCalendarDate("implanted_on", implantedOn)
+		if v.fail(err) {
+			return nil, v.err()
+		}
+		if err == nil && a.BirthDate != "" && implantedOn < a.BirthDate {
+			if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the birth date", Field: "implanted_on"}) {
+				return nil, v.err()
+			}
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := MicrochipRegistered{
+		CommandID:   cmd.CommandID,
+		CatID:       a.CatID,
+		ChipNumber:  chipNumber,
+		ImplantedOn: implantedOn,
+	}
+	return []Event{event}, nil
+}
+
+// NormalizeMicrochip strips spaces and dashes and validates the result as an
+// ISO 11784 code. Code 000 is unassigned and 999 is reserved for test
+// transponders; both are rejected.
+func NormalizeMicrochip(value string) (string, error) {
+	chipNumber := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(value))
+	if chipNumber == "" {
+		return "", Rejection{Code: CodeInvalidMicrochip, Message: "must not be empty", Field: "chip_number"}
+	}
+	if len(chipNumber) != MicrochipDigits {
+		return "", Rejection{Code: CodeInvalidMicrochip, Message: "must have 15 digits", Field: "chip_number"}
+	}
+	for _, r := range chipNumber {
+		if r < '0' || r > '9' {
+			return "", Rejection{Code: CodeInvalidMicrochip, Message: "must contain only digits", Field: "chip_number"}
+		}
+	}
+	switch chipNumber[:3] {
+	case "000":
+		return "", Rejection{Code: CodeInvalidMicrochip, Message: "country or manufacturer code 000 is unassigned", Field: "chip_number"}
+	case "999":
+		return "", Rejection{Code: CodeInvalidMicrochip, Message: "code 999 is reserved for test transponders", Field: "chip_number"}
+	}
+	return chipNumber, nil
+}
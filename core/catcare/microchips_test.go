@@ -0,0 +1,73 @@
+package catcare
+
+import "testing"
+
+func TestRegisterMicrochipGivenRegisteredCatWhenGroupedNumberThenEmitsNormalizedChip(t *testing.T) {
+	aggregate, err := LoadFrom(bornCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(RegisterMicrochip{
+		CommandID:   "cmd-chip",
+		ChipNumber:  " 985 112-003 456 789 ",
+		ImplantedOn: "2023-03-01",
+	})
+	if err != nil {
+		t.Fatalf("decide register microchip: %v", err)
+	}
+	event, ok := events[0].(MicrochipRegistered)
+	if !ok {
+		t.Fatalf("expected MicrochipRegistered, got %T", events[0])
+	}
+	if event.ChipNumber != "985112003456789" {
+		t.Fatalf("expected normalized chip number, got %q", event.ChipNumber)
+	}
+
+	if err := aggregate.Apply(event); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	_, err = aggregate.Decide(RegisterMicrochip{CommandID: "cmd-chip-again", ChipNumber: "985112003456789"})
+	rejection, ok := err.(Rejection)
+	if !ok || rejection.Code != CodeDuplicateMicrochip {
+		t.Fatalf("expected %q, got %v", CodeDuplicateMicrochip, err)
+	}
+}
+
+func TestRegisterMicrochipGivenRegisteredCatWhenInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(bornCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		chipNumber  string
+		implantedOn string
+		code        string
+	}{
+		{name: "empty", chipNumber: "", code: CodeInvalidMicrochip},
+		{name: "too short", chipNumber: "98511200345678", code: CodeInvalidMicrochip},
+		{name: "too long", chipNumber: "9851120034567890", code: CodeInvalidMicrochip},
+		{name: "letters", chipNumber: "98511200345678A", code: CodeInvalidMicrochip},
+		{name: "unassigned code", chipNumber: "000112003456789", code: CodeInvalidMicrochip},
+		{name: "test transponder", chipNumber: "999112003456789", code: CodeInvalidMicrochip},
+		{name: "implanted before birth", chipNumber: "985112003456789", implantedOn: "2022-12-31", code: CodeInvalidDate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(RegisterMicrochip{CommandID: "cmd-chip", ChipNumber: tc.chipNumber, ImplantedOn: tc.implantedOn})
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package catcare
+
+import "time"
+
+const (
+	RecurrenceUnitDay   = "DAY"
+	RecurrenceUnitWeek  = "WEEK"
+	RecurrenceUnitMonth = "MONTH"
+	RecurrenceUnitYear  = "YEAR"
+)
+
+const (
+	MinRecurrenceInterval = 1
+	MaxRecurrenceInterval = 366
+)
+
+// Recurrence describes how a care item rolls over once completed.
+// Anchor is the due date of the first occurrence; it is minted by the core
+// when the item is scheduled so month and year rules keep the original day
+// (a yearly item anchored on Feb 29 lands on Feb 28 in non-leap years and
+// returns to Feb 29 in leap years).
+type Recurrence struct {
+	Unit     string
+	Interval int
+	Anchor   string
+}
+
+func validateRecurrence(recurrence *Recurrence) error {
+	if recurrence == nil {
+		return nil
+	}
+	switch recurrence.Unit {
+	case RecurrenceUnitDay, RecurrenceUnitWeek, RecurrenceUnitMonth, RecurrenceUnitYear:
+	default:
+		return Rejection{Code: CodeInvalidRecurrence, Message: "unit must be one of DAY, WEEK, MONTH, YEAR", Field: "recurrence.unit"}
+	}
+	if recurrence.Interval < MinRecurrenceInterval || recurrence.Interval > MaxRecurrenceInterval {
+		return Rejection{Code: CodeInvalidRecurrence, Message: "interval outside allowed range", Field: "recurrence.interval"}
+	}
+	return nil
+}
+
+// nextOccurrence returns the first due date of the recurrence strictly after
+// dueAt. It only reads the event data it is given, never the wall clock.
+func nextOccurrence(recurrence Recurrence, dueAt string) (string, error) {
+	current, err := time.Parse(time.RFC3339, dueAt)
+	if err != nil {
+		return "", err
+	}
+	anchor, err := time.Parse(time.RFC3339, recurrence.Anchor)
+	if err != nil {
+		return "", err
+	}
+
+	switch recurrence.Unit {
+	case RecurrenceUnitDay:
+		return current.AddDate(0, 0, recurrence.Interval).Format(time.RFC3339), nil
+	case RecurrenceUnitWeek:
+		return current.AddDate(0, 0, 7*recurrence.Interval).Format(time.RFC3339), nil
+	}
+
+	months := recurrence.Interval
+	if recurrence.Unit == RecurrenceUnitYear {
+		months *= 12
+	}
+	for step := months; ; step += months {
+		next := addMonthsClamped(anchor, step)
+		if next.After(current) {
+			return next.Format(time.RFC3339), nil
+		}
+	}
+}
+
+// addMonthsClamped adds months to t, clamping the day to the last day of the
+// target month instead of overflowing into the next one like time.AddDate.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	total := int(month) - 1 + months
+	targetYear := year + total/12
+	targetMonth := time.Month(total%12 + 1)
+	lastDay := time.Date(targetYear, targetMonth+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	hour, minute, second := t.Clock()
+	return time.Date(targetYear, targetMonth, day, hour, minute, second, t.Nanosecond(), t.Location())
+}
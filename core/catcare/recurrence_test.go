@@ -0,0 +1,166 @@
+package catcare
+
+import "testing"
+
+func TestCompleteCareItemGivenRecurringItemWhenCompleteThenSchedulesNextOccurrence(t *testing.T) {
+	cases := []struct {
+		name       string
+		recurrence Recurrence
+		dueAt      string
+		nextDueAt  string
+	}{
+		{
+			name:       "every 30 days",
+			recurrence: Recurrence{Unit: RecurrenceUnitDay, Interval: 30, Anchor: "2026-01-15T09:00:00Z"},
+			dueAt:      "2026-01-15T09:00:00Z",
+			nextDueAt:  "2026-02-14T09:00:00Z",
+		},
+		{
+			name:       "every 2 weeks",
+			recurrence: Recurrence{Unit: RecurrenceUnitWeek, Interval: 2, Anchor: "2026-01-15T09:00:00+09:00"},
+			dueAt:      "2026-01-15T09:00:00+09:00",
+			nextDueAt:  "2026-01-29T09:00:00+09:00",
+		},
+		{
+			name:       "monthly clamps to month end",
+			recurrence: Recurrence{Unit: RecurrenceUnitMonth, Interval: 1, Anchor: "2026-01-31T09:00:00Z"},
+			dueAt:      "2026-01-31T09:00:00Z",
+			nextDueAt:  "2026-02-28T09:00:00Z",
+		},
+		{
+			name:       "monthly returns to anchor day",
+			recurrence: Recurrence{Unit: RecurrenceUnitMonth, Interval: 1, Anchor: "2026-01-31T09:00:00Z"},
+			dueAt:      "2026-02-28T09:00:00Z",
+			nextDueAt:  "2026-03-31T09:00:00Z",
+		},
+		{
+			name:       "yearly anniversary on leap day",
+			recurrence: Recurrence{Unit: RecurrenceUnitYear, Interval: 1, Anchor: "2024-02-29T09:00:00Z"},
+			dueAt:      "2027-02-28T09:00:00Z",
+			nextDueAt:  "2028-02-29T09:00:00Z",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recurrence := tc.recurrence
+			aggregate, err := LoadFrom(append(registeredCatEvents(), CareItemScheduled{
+				CommandID:  "cmd-schedule",
+				ItemID:     "item-cmd-schedule",
+				Kind:       CareItemKindVaccine,
+				Title:      "Flea treatment",
+				DueAt:      tc.dueAt,
+				Recurrence: &recurrence,
+			}))
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			events, err := aggregate.Decide(CompleteCareItem{
+				CommandID:   "cmd-complete",
+				ItemID:      "item-cmd-schedule",
+				CompletedAt: "2026-06-01T10:00:00Z",
+			})
+			if err != nil {
+				t.Fatalf("decide complete care item: %v", err)
+			}
+			if len(events) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(events))
+			}
+			if _, ok := events[0].(CareItemCompleted); !ok {
+				t.Fatalf("expected CareItemCompleted first, got %T", events[0])
+			}
+			next, ok := events[1].(CareItemScheduled)
+			if !ok {
+				t.Fatalf("expected CareItemScheduled second, got %T", events[1])
+			}
+			if next.DueAt != tc.nextDueAt {
+				t.Fatalf("expected next due %q, got %q", tc.nextDueAt, next.DueAt)
+			}
+			if next.ItemID != "item-cmd-complete" || next.FollowsItemID != "item-cmd-schedule" {
+				t.Fatalf("unexpected next item ids %+v", next)
+			}
+			if next.Recurrence == nil || *next.Recurrence != tc.recurrence {
+				t.Fatalf("expected recurrence to carry over, got %+v", next.Recurrence)
+			}
+		})
+	}
+}
+
+func TestScheduleCareItemGivenRecurrenceWhenScheduleThenMintsAnchor(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(ScheduleCareItem{
+		CommandID:  "cmd-schedule",
+		Kind:       CareItemKindVaccine,
+		Title:      "Rabies booster",
+		DueAt:      "2026-03-01T09:00:00Z",
+		Recurrence: &Recurrence{Unit: RecurrenceUnitYear, Interval: 1, Anchor: "1999-01-01T00:00:00Z"},
+	})
+	if err != nil {
+		t.Fatalf("decide schedule care item: %v", err)
+	}
+	event := events[0].(CareItemScheduled)
+	if event.Recurrence.Anchor != "2026-03-01T09:00:00Z" {
+		t.Fatalf("expected anchor minted from due date, got %q", event.Recurrence.Anchor)
+	}
+}
+
+func TestScheduleCareItemGivenInvalidRecurrenceWhenScheduleThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		dueAt string
+		rule  Recurrence
+		code  string
+	}{
+		{
+			name:  "unknown unit",
+			dueAt: "2026-03-01T09:00:00Z",
+			rule:  Recurrence{Unit: "FORTNIGHT", Interval: 1},
+			code:  CodeInvalidRecurrence,
+		},
+		{
+			name:  "zero interval",
+			dueAt: "2026-03-01T09:00:00Z",
+			rule:  Recurrence{Unit: RecurrenceUnitDay, Interval: 0},
+			code:  CodeInvalidRecurrence,
+		},
+		{
+			name:  "due date not RFC3339",
+			dueAt: "next tuesday",
+			rule:  Recurrence{Unit: RecurrenceUnitWeek, Interval: 1},
+			code:  CodeInvalidDate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := tc.rule
+			_, err := aggregate.Decide(ScheduleCareItem{
+				CommandID:  "cmd-schedule",
+				Kind:       CareItemKindOther,
+				Title:      "Flea treatment",
+				DueAt:      tc.dueAt,
+				Recurrence: &rule,
+			})
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
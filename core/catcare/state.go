@@ -0,0 +1,104 @@
+package catcare
+
+import (
+	"maps"
+	"slices"
+)
+
+// StateFormat identifies the shape and meaning of State. Bump it whenever a
+// field is added to, removed from, or reinterpreted in the aggregate state:
+// snapshots taken under another format are ignored and the stream replayed.
+const StateFormat = 1
+
+// State is the serializable form of everything Apply has folded into the
+// aggregate, including the bookkeeping Decide relies on (processed command
+// IDs, retracted weight entries). Options are not state; they are supplied
+// again when the aggregate is restored.
+type State struct {
+	CatID               string
+	Name                string
+	NameHistory         []string
+	BirthDate           string
+	Registered          bool
+	Status              string
+	CaretakerRef        string
+	Microchips          []string
+	WeightEntries       []WeightLogged
+	IdealWeightRange    *IdealWeightRange
+	CareItems           map[string]CareItem
+	Anomalies           map[string]Anomaly
+	TreatmentPlans      map[string]TreatmentPlan
+	Vaccinations        []VaccinationRecorded
+	VetVisits           map[string]VetVisitRecorded
+	Prescriptions       map[string]Prescription
+	DietPlan            *DietPlan
+	Meals               []MealLogged
+	RetractedEntryIDs   []string
+	ProcessedCommandIDs []string
+}
+
+// State returns a copy of the aggregate state. Set-like fields are sorted so
+// equal aggregates produce equal states.
+func (a *CatCare) State() State {
+	return State{
+		CatID:               a.CatID,
+		Name:                a.Name,
+		NameHistory:         slices.Clone(a.NameHistory),
+		BirthDate:           a.BirthDate,
+		Registered:          a.Registered,
+		Status:              a.Status,
+		CaretakerRef:        a.CaretakerRef,
+		Microchips:          slices.Clone(a.Microchips),
+		WeightEntries:       slices.Clone(a.WeightEntries),
+		IdealWeightRange:    clonePointer(a.IdealWeightRange),
+		CareItems:           maps.Clone(a.CareItems),
+		Anomalies:           maps.Clone(a.Anomalies),
+		TreatmentPlans:      maps.Clone(a.TreatmentPlans),
+		Vaccinations:        slices.Clone(a.Vaccinations),
+		VetVisits:           maps.Clone(a.VetVisits),
+		Prescriptions:       maps.Clone(a.Prescriptions),
+		DietPlan:            clonePointer(a.DietPlan),
+		Meals:               slices.Clone(a.Meals),
+		RetractedEntryIDs:   slices.Sorted(maps.Keys(a.retractedEntryIDs)),
+		ProcessedCommandIDs: slices.Sorted(maps.Keys(a.processedCommandIDs)),
+	}
+}
+
+// Restore rebuilds an aggregate from a State, as if the events that produced
+// it had been applied to New(options...). Later events are applied on top.
+func Restore(state State, options ...Option) *CatCare {
+	aggregate := New(options...)
+	aggregate.CatID = state.CatID
+	aggregate.Name = state.Name
+	aggregate.NameHistory = slices.Clone(state.NameHistory)
+	aggregate.BirthDate = state.BirthDate
+	aggregate.Registered = state.Registered
+	aggregate.Status = state.Status
+	aggregate.CaretakerRef = state.CaretakerRef
+	aggregate.Microchips = slices.Clone(state.Microchips)
+	aggregate.WeightEntries = slices.Clone(state.WeightEntries)
+	aggregate.IdealWeightRange = clonePointer(state.IdealWeightRange)
+	maps.Copy(aggregate.CareItems, state.CareItems)
+	maps.Copy(aggregate.Anomalies, state.Anomalies)
+	maps.Copy(aggregate.TreatmentPlans, state.TreatmentPlans)
+	aggregate.Vaccinations = slices.Clone(state.Vaccinations)
+	maps.Copy(aggregate.VetVisits, state.VetVisits)
+	maps.Copy(aggregate.Prescriptions, state.Prescriptions)
+	aggregate.DietPlan = clonePointer(state.DietPlan)
+	aggregate.Meals = slices.Clone(state.Meals)
+	for _, entryID := range state.RetractedEntryIDs {
+		aggregate.retractedEntryIDs[entryID] = struct{}{}
+	}
+	for _, commandID := range state.ProcessedCommandIDs {
+		aggregate.processedCommandIDs[commandID] = struct{}{}
+	}
+	return aggregate
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
@@ -0,0 +1,88 @@
+package catcare
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func snapshotEvents() []Event {
+	return append(prescribedEvents(),
+		WeightLogged{CommandID: "cmd-weight-1", EntryID: "weight-cmd-weight-1", At: "2026-02-10T10:00:00Z", Grams: 4200, BodyConditionScore: 5},
+		WeightLogged{CommandID: "cmd-weight-2", EntryID: "weight-cmd-weight-2", At: "2026-02-11T10:00:00Z", Grams: 4150},
+		WeightEntryRetracted{CommandID: "cmd-retract", EntryID: "weight-cmd-weight-2", Reason: "scale error"},
+		CareItemScheduled{CommandID: "cmd-schedule", ItemID: "item-cmd-schedule", Kind: CareItemKindVaccine, Title: "Rabies", DueAt: "2026-03-01T09:00:00Z"},
+		IdealWeightRangeSet{CommandID: "cmd-ideal", MinGrams: 3800, MaxGrams: 4500, VetName: "Dr. Lima"},
+	)
+}
+
+func TestStateGivenAggregateWhenSerializedAndRestoredThenStateRoundTrips(t *testing.T) {
+	aggregate, err := LoadFrom(snapshotEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	payload, err := json.Marshal(aggregate.State())
+	if err != nil {
+		t.Fatalf("marshal state: %v", err)
+	}
+	var decoded State
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("unmarshal state: %v", err)
+	}
+
+	restored := Restore(decoded)
+	if !reflect.DeepEqual(restored.State(), aggregate.State()) {
+		t.Fatalf("restored state = %+v, want %+v", restored.State(), aggregate.State())
+	}
+	if want := []string{"weight-cmd-weight-2"}; !reflect.DeepEqual(decoded.RetractedEntryIDs, want) {
+		t.Fatalf("retracted entries = %v, want %v", decoded.RetractedEntryIDs, want)
+	}
+}
+
+func TestRestoreGivenStateWhenDecideThenMatchesAggregateLoadedFromEvents(t *testing.T) {
+	loaded, err := LoadFrom(snapshotEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+	restored := Restore(loaded.State())
+
+	cases := []struct {
+		name    string
+		command Command
+	}{
+		{name: "already processed command", command: LogWeight{CommandID: "cmd-weight-1", At: "2026-02-12T10:00:00Z", Grams: 4200}},
+		{name: "retracted entry", command: CorrectWeightEntry{CommandID: "cmd-correct", EntryID: "weight-cmd-weight-2", Grams: 4100, Reason: "typo"}},
+		{name: "dose too soon", command: RecordDoseGiven{CommandID: "cmd-dose-2", PrescriptionID: "prescription-cmd-prescribe", GivenAt: "2026-02-10T10:00:00Z"}},
+		{name: "new weight", command: LogWeight{CommandID: "cmd-weight-3", At: "2026-02-12T10:00:00Z", Grams: 4250}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wantEvents, wantErr := loaded.Decide(tc.command)
+			gotEvents, gotErr := restored.Decide(tc.command)
+			if !reflect.DeepEqual(gotEvents, wantEvents) || !reflect.DeepEqual(gotErr, wantErr) {
+				t.Fatalf("restored decided %v, %v; loaded decided %v, %v", gotEvents, gotErr, wantEvents, wantErr)
+			}
+		})
+	}
+}
+
+func TestRestoreGivenStateWhenApplyingThenDoesNotMutateTheState(t *testing.T) {
+	loaded, err := LoadFrom(snapshotEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+	state := loaded.State()
+	restored := Restore(state)
+
+	if err := restored.Apply(CareItemCanceled{CommandID: "cmd-cancel", ItemID: "item-cmd-schedule", Reason: "moved"}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if state.CareItems["item-cmd-schedule"].Status != CareItemStatusScheduled {
+		t.Fatalf("state care item status = %q, want it untouched", state.CareItems["item-cmd-schedule"].Status)
+	}
+	if len(state.ProcessedCommandIDs) != len(loaded.State().ProcessedCommandIDs) {
+		t.Fatalf("state processed command ids changed")
+	}
+}
@@ -0,0 +1,96 @@
+package catcare
+
+import (
+	"strconv"
+
+	"github.com/wastingnotime/zeroapps/core/internal/freetext"
+)
+
+// TextPolicy bounds free-text input (names, titles, notes, references, ...).
+// Text is normalized to NFC and trimmed before it is checked; lengths count
+// runes. Only fields listed in MultilineFields may contain line breaks and
+// tabs.
+type TextPolicy struct {
+	DefaultMaxRunes int
+	MaxRunes        map[string]int
+	MultilineFields map[string]bool
+}
+
+// DefaultTextPolicy returns the policy New uses when WithTextPolicy is not
+// given. Each call builds new maps, so callers may adjust the result.
+func DefaultTextPolicy() TextPolicy {
+	return TextPolicy{
+		DefaultMaxRunes: 200,
+		MaxRunes: map[string]int{
+			"name":     80,
+			"new_name": 80,
+			"notes":    4000,
+			"protocol": 4000,
+			"outcome":  1000,
+			"reason":   1000,
+		},
+		MultilineFields: map[string]bool{
+			"notes":    true,
+			"protocol": true,
+			"outcome":  true,
+			"reason":   true,
+		},
+	}
+}
+
+func WithTextPolicy(policy TextPolicy) Option {
+	return func(a *CatCare) {
+		a.textPolicy = policy
+	}
+}
+
+// text applies the text policy to an optional free-text field and returns
+// the normalized value.
+func (a *CatCare) text(field string, value string) (string, error) {
+	limit := a.textPolicy.maxRunes(field)
+	normalized, problem := freetext.Clean(value, limit, a.textPolicy.MultilineFields[field])
+	switch problem {
+	case freetext.InvalidUTF8:
+		return "", Rejection{Code: CodeInvalidCharacters, Message: "must be valid UTF-8", Field: field}
+	case freetext.InvalidCharacters:
+		return "", Rejection{Code: CodeInvalidCharacters, Message: "must not contain control or invisible characters", Field: field}
+	case freetext.TooLong:
+		return "", Rejection{Code: CodeTextTooLong, Message: "must be at most " + strconv.Itoa(limit) + " characters", Field: field}
+	}
+	return normalized, nil
+}
+
+// requiredText is text for fields that must not be empty; emptyCode is the
+// field's own rejection code (e.g. invalid_name).
+func (a *CatCare) requiredText(field string, value string, emptyCode string) (string, error) {
+	normalized, err := a.text(field, value)
+	if err != nil {
+		return "", err
+	}
+	if normalized == "" {
+		return "", Rejection{Code: emptyCode, Message: "must not be empty", Field: field}
+	}
+	return normalized, nil
+}
+
+// textList applies the policy to each entry of a list field, dropping empty
+// and duplicate entries.
+func (a *CatCare) textList(field string, values []string) ([]string, error) {
+	normalized := make([]string, 0, len(values))
+	for index, value := range values {
+		entry, err := a.text(field, value)
+		if rejection, ok := err.(Rejection); ok {
+			rejection.Field = field + "[" + strconv.Itoa(index) + "]"
+			return nil, rejection
+		}
+		normalized = append(normalized, entry)
+	}
+	return normalizeTags(normalized), nil
+}
+
+func (p TextPolicy) maxRunes(field string) int {
+	if limit, ok := p.MaxRunes[field]; ok {
+		return limit
+	}
+	return p.DefaultMaxRunes
+}
@@ -0,0 +1,106 @@
+package catcare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterCatGivenDecomposedNameWhenRegisterThenStoresNFC(t *testing.T) {
+	aggregate := New()
+
+	events, err := aggregate.Decide(RegisterCat{CommandID: "cmd-1", Name: "  Zoë "})
+	if err != nil {
+		t.Fatalf("decide register cat: %v", err)
+	}
+	if name := events[0].(CatRegistered).Name; name != "Zo\u00eb" {
+		t.Fatalf("expected NFC name, got %q", name)
+	}
+}
+
+func TestDecideGivenFreeTextWhenPolicyViolatedThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		command Command
+		code    string
+		field   string
+	}{
+		{name: "control character in name", command: RenameCat{CommandID: "cmd-2", NewName: "Mi\x07so"}, code: CodeInvalidCharacters, field: "new_name"},
+		{name: "line break in name", command: RenameCat{CommandID: "cmd-2", NewName: "Mi\nso"}, code: CodeInvalidCharacters, field: "new_name"},
+		{name: "zero width space", command: RenameCat{CommandID: "cmd-2", NewName: "Mi\u200bso"}, code: CodeInvalidCharacters, field: "new_name"},
+		{name: "bidi override", command: RenameCat{CommandID: "cmd-2", NewName: "\u202eosiM"}, code: CodeInvalidCharacters, field: "new_name"},
+		{name: "hangul filler", command: RenameCat{CommandID: "cmd-2", NewName: "\u3164"}, code: CodeInvalidCharacters, field: "new_name"},
+		{name: "invalid utf-8", command: RenameCat{CommandID: "cmd-2", NewName: "Mi\xffso"}, code: CodeInvalidCharacters, field: "new_name"},
+		{name: "name too long", command: RenameCat{CommandID: "cmd-2", NewName: strings.Repeat("a", 81)}, code: CodeTextTooLong, field: "new_name"},
+		{name: "notes too long", command: LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4200, Notes: strings.Repeat("n", 4001)}, code: CodeTextTooLong, field: "notes"},
+		{name: "tag with control character", command: ReportAnomaly{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Summary: "Vomiting", Severity: SeverityLow, Tags: []string{"gi", "a\x00b"}}, code: CodeInvalidCharacters, field: "tags[1]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.command)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %q on %q", tc.code, tc.field, rejection.Code, rejection.Field)
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenMultilineNotesWhenLogThenKeepsLineBreaks(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4200, Notes: "after meal\r\n\tscale B"})
+	if err != nil {
+		t.Fatalf("decide log weight: %v", err)
+	}
+	if notes := events[0].(WeightLogged).Notes; notes != "after meal\n\tscale B" {
+		t.Fatalf("unexpected notes %q", notes)
+	}
+}
+
+func TestRenameCatGivenCustomTextPolicyWhenNameExceedsLimitThenRejects(t *testing.T) {
+	policy := TextPolicy{DefaultMaxRunes: 200, MaxRunes: map[string]int{"new_name": 4}}
+	aggregate, err := LoadFrom(registeredCatEvents(), WithTextPolicy(policy))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	if _, err := aggregate.Decide(RenameCat{CommandID: "cmd-2", NewName: "Zoë"}); err != nil {
+		t.Fatalf("expected 3-rune name to pass: %v", err)
+	}
+	_, err = aggregate.Decide(RenameCat{CommandID: "cmd-3", NewName: "Mochi"})
+	rejection, ok := err.(Rejection)
+	if !ok || rejection.Code != CodeTextTooLong {
+		t.Fatalf("expected %q, got %v", CodeTextTooLong, err)
+	}
+}
+
+func TestRenameCatGivenAdjustedDefaultTextPolicyWhenNewAggregateThenKeepsDefaults(t *testing.T) {
+	policy := DefaultTextPolicy()
+	policy.MaxRunes["new_name"] = 4
+	if _, err := LoadFrom(registeredCatEvents(), WithTextPolicy(policy)); err != nil {
+		t.Fatalf("load customized aggregate: %v", err)
+	}
+
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+	if _, err := aggregate.Decide(RenameCat{CommandID: "cmd-2", NewName: "Mochi"}); err != nil {
+		t.Fatalf("expected the default name limit to be unaffected, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package catcare
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TreatmentPlanEndedReason is recorded on care items canceled because their
+// owning treatment plan ended.
+const TreatmentPlanEndedReason = "treatment plan ended"
+
+type TreatmentPlan struct {
+	PlanID    string
+	Title     string
+	StartedAt string
+	Protocol  string
+	Notes     string
+	Ended     bool
+	EndedAt   string
+	Outcome   string
+	EndNotes  string
+}
+
+type StartTreatmentPlan struct {
+	CommandID string
+	Title     string
+	StartedAt string
+	Protocol  string
+	Notes     string
+}
+
+func (c StartTreatmentPlan) commandName() string { return "StartTreatmentPlan" }
+func (c StartTreatmentPlan) commandID() string   { return c.CommandID }
+
+// UpdateTreatmentPlan is a patch: nil fields are left unchanged.
+type UpdateTreatmentPlan struct {
+	CommandID string
+	PlanID    string
+	Title     *string
+	Protocol  *string
+	Notes     *string
+}
+
+func (c UpdateTreatmentPlan) commandName() string { return "UpdateTreatmentPlan" }
+func (c UpdateTreatmentPlan) commandID() string   { return c.CommandID }
+
+type EndTreatmentPlan struct {
+	CommandID string
+	PlanID    string
+	EndedAt   string
+	Outcome   string
+	Notes     string
+}
+
+func (c EndTreatmentPlan) commandName() string { return "EndTreatmentPlan" }
+func (c EndTreatmentPlan) commandID() string   { return c.CommandID }
+
+type TreatmentPlanStarted struct {
+	CommandID string
+	PlanID    string
+	Title     string
+	StartedAt string
+	Protocol  string
+	Notes     string
+}
+
+func (e TreatmentPlanStarted) eventName() string { return "TreatmentPlanStarted" }
+func (e TreatmentPlanStarted) commandID() string { return e.CommandID }
+
+type TreatmentPlanUpdated struct {
+	CommandID string
+	PlanID    string
+	Title     *string
+	Protocol  *string
+	Notes     *string
+}
+
+func (e TreatmentPlanUpdated) eventName() string { return "TreatmentPlanUpdated" }
+func (e TreatmentPlanUpdated) commandID() string { return e.CommandID }
+
+type TreatmentPlanEnded struct {
+	CommandID string
+	PlanID    string
+	EndedAt   string
+	Outcome   string
+	Notes     string
+}
+
+func (e TreatmentPlanEnded) eventName() string { return "TreatmentPlanEnded" }
+func (e TreatmentPlanEnded) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideStartTreatmentPlan(cmd StartTreatmentPlan) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	title, err := a.requiredText("title", cmd.Title, CodeInvalidTitle)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	protocol, err := a.text("protocol", cmd.Protocol)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	startedAt := strings.TrimSpace(cmd.StartedAt)
+	if startedAt == "" {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: "started_at"}) {
+			return nil, v.err()
+		}
+	} else if _, err := a.scheduledTimestamp("started_at", startedAt); v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := TreatmentPlanStarted{
+		CommandID: cmd.CommandID,
+		PlanID:    mintID("plan", cmd.CommandID),
+		Title:     title,
+		StartedAt: startedAt,
+		Protocol:  protocol,
+		Notes:     notes,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideUpdateTreatmentPlan(cmd UpdateTreatmentPlan) ([]Event, error) {
+	if _, err := a.activeTreatmentPlan(cmd.PlanID); err != nil {
+		return nil, err
+	}
+	if cmd.Title == nil && cmd.Protocol == nil && cmd.Notes == nil {
+		return nil, Rejection{Code: CodeEmptyPatch, Message: "at least one field must change"}
+	}
+	v := a.validation()
+	title, err := a.textPtr("title", cmd.Title)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if title != nil && *title == "" {
+		if v.fail(Rejection{Code: CodeInvalidTitle, Message: "must not be empty", Field: "title"}) {
+			return nil, v.err()
+		}
+	}
+	protocol, err := a.textPtr("protocol", cmd.Protocol)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.textPtr("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := TreatmentPlanUpdated{
+		CommandID: cmd.CommandID,
+		PlanID:    cmd.PlanID,
+		Title:     title,
+		Protocol:  protocol,
+		Notes:     notes,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideEndTreatmentPlan(cmd EndTreatmentPlan) ([]Event, error) {
+	plan, err := a.activeTreatmentPlan(cmd.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	v := a.validation()
+	endedAt := strings.TrimSpace(cmd.EndedAt)
+	ended, err := a.plausibleTimestamp("ended_at", endedAt)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err == nil {
+		if started, err := time.Parse(time.RFC3339, plan.StartedAt); err == nil && ended.Before(started) {
+			if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the start time", Field: "ended_at"}) {
+				return nil, v.err()
+			}
+		}
+	}
+	outcome, err := a.text("outcome", cmd.Outcome)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	events := []Event{TreatmentPlanEnded{
+		CommandID: cmd.CommandID,
+		PlanID:    cmd.PlanID,
+		EndedAt:   endedAt,
+		Outcome:   outcome,
+		Notes:     notes,
+	}}
+	for _, itemID := range a.openPlanItemIDs(cmd.PlanID) {
+		events = append(events, CareItemCanceled{
+			CommandID: cmd.CommandID,
+			ItemID:    itemID,
+			Reason:    TreatmentPlanEndedReason,
+		})
+	}
+	return events, nil
+}
+
+func (a *CatCare) activeTreatmentPlan(planID string) (TreatmentPlan, error) {
+	if !a.Registered {
+		return TreatmentPlan{}, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	if strings.TrimSpace(planID) == "" {
+		return TreatmentPlan{}, Rejection{Code: CodeInvalidPlanID, Message: "must not be empty", Field: "plan_id"}
+	}
+	plan, exists := a.TreatmentPlans[planID]
+	if !exists {
+		return TreatmentPlan{}, Rejection{Code: CodeUnknownTreatmentPlan, Message: "treatment plan does not exist", Field: "plan_id"}
+	}
+	if plan.Ended {
+		return TreatmentPlan{}, Rejection{Code: CodeTreatmentPlanEnded, Message: "treatment plan already ended", Field: "plan_id"}
+	}
+	return plan, nil
+}
+
+// openPlanItemIDs lists the still-scheduled care items owned by a plan, sorted
+// so the cancellations emitted on EndTreatmentPlan are deterministic.
+func (a *CatCare) openPlanItemIDs(planID string) []string {
+	var itemIDs []string
+	for itemID, item := range a.CareItems {
+		if item.PlanID == planID && item.Status == CareItemStatusScheduled {
+			itemIDs = append(itemIDs, itemID)
+		}
+	}
+	sort.Strings(itemIDs)
+	return itemIDs
+}
+
+// textPtr applies the text policy to an optional patch field.
+func (a *CatCare) textPtr(field string, value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	normalized, err := a.text(field, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
@@ -0,0 +1,244 @@
+package catcare
+
+import (
+	"testing"
+	"time"
+)
+
+func treatmentPlanEvents() []Event {
+	return append(registeredCatEvents(), TreatmentPlanStarted{
+		CommandID: "cmd-plan",
+		PlanID:    "plan-cmd-plan",
+		Title:     "Amoxicillin course",
+		StartedAt: "2026-02-01T08:00:00Z",
+	})
+}
+
+func TestStartTreatmentPlanGivenRegisteredCatWhenStartThenEmitsTreatmentPlanStarted(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(StartTreatmentPlan{
+		CommandID: "cmd-plan-1",
+		Title:     "Amoxicillin course",
+		StartedAt: "2026-02-01T08:00:00Z",
+		Protocol:  "50mg twice daily for 14 days",
+	})
+	if err != nil {
+		t.Fatalf("decide start treatment plan: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event, ok := events[0].(TreatmentPlanStarted)
+	if !ok {
+		t.Fatalf("expected TreatmentPlanStarted, got %T", events[0])
+	}
+	if event.PlanID != "plan-cmd-plan-1" {
+		t.Fatalf("expected deterministic plan id, got %q", event.PlanID)
+	}
+}
+
+func TestUpdateTreatmentPlanGivenActivePlanWhenUpdateThenPatchesState(t *testing.T) {
+	aggregate, err := LoadFrom(treatmentPlanEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	protocol := " 50mg twice daily for 21 days "
+	events, err := aggregate.Decide(UpdateTreatmentPlan{
+		CommandID: "cmd-plan-update",
+		PlanID:    "plan-cmd-plan",
+		Protocol:  &protocol,
+	})
+	if err != nil {
+		t.Fatalf("decide update treatment plan: %v", err)
+	}
+	if err := aggregate.Apply(events[0]); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+
+	plan := aggregate.TreatmentPlans["plan-cmd-plan"]
+	if plan.Protocol != "50mg twice daily for 21 days" {
+		t.Fatalf("expected patched protocol, got %q", plan.Protocol)
+	}
+	if plan.Title != "Amoxicillin course" {
+		t.Fatalf("expected untouched title, got %q", plan.Title)
+	}
+}
+
+func TestEndTreatmentPlanGivenPlanWithOpenDosesWhenEndThenCancelsRemainingDoses(t *testing.T) {
+	aggregate, err := LoadFrom(append(treatmentPlanEvents(),
+		CareItemScheduled{
+			CommandID: "cmd-dose-1",
+			ItemID:    "item-cmd-dose-1",
+			Kind:      CareItemKindTreatmentStep,
+			Title:     "Dose 1",
+			DueAt:     "2026-02-01T08:00:00Z",
+			PlanID:    "plan-cmd-plan",
+		},
+		CareItemCompleted{
+			CommandID:   "cmd-dose-1-done",
+			ItemID:      "item-cmd-dose-1",
+			CompletedAt: "2026-02-01T08:05:00Z",
+		},
+		CareItemScheduled{
+			CommandID: "cmd-dose-3",
+			ItemID:    "item-cmd-dose-3",
+			Kind:      CareItemKindTreatmentStep,
+			Title:     "Dose 3",
+			DueAt:     "2026-02-02T08:00:00Z",
+			PlanID:    "plan-cmd-plan",
+		},
+		CareItemScheduled{
+			CommandID: "cmd-dose-2",
+			ItemID:    "item-cmd-dose-2",
+			Kind:      CareItemKindTreatmentStep,
+			Title:     "Dose 2",
+			DueAt:     "2026-02-01T20:00:00Z",
+			PlanID:    "plan-cmd-plan",
+		},
+		CareItemScheduled{
+			CommandID: "cmd-unrelated",
+			ItemID:    "item-cmd-unrelated",
+			Kind:      CareItemKindVaccine,
+			Title:     "Rabies booster",
+			DueAt:     "2026-03-01T09:00:00Z",
+		},
+	))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(EndTreatmentPlan{
+		CommandID: "cmd-plan-end",
+		PlanID:    "plan-cmd-plan",
+		EndedAt:   "2026-02-01T21:00:00Z",
+		Outcome:   "stopped early: side effects",
+	})
+	if err != nil {
+		t.Fatalf("decide end treatment plan: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if _, ok := events[0].(TreatmentPlanEnded); !ok {
+		t.Fatalf("expected TreatmentPlanEnded first, got %T", events[0])
+	}
+	expected := []string{"item-cmd-dose-2", "item-cmd-dose-3"}
+	for index, itemID := range expected {
+		canceled, ok := events[index+1].(CareItemCanceled)
+		if !ok {
+			t.Fatalf("expected CareItemCanceled, got %T", events[index+1])
+		}
+		if canceled.ItemID != itemID {
+			t.Fatalf("expected canceled item %q, got %q", itemID, canceled.ItemID)
+		}
+	}
+}
+
+func TestTreatmentPlanGivenEndedPlanWhenDecideThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(append(treatmentPlanEvents(), TreatmentPlanEnded{
+		CommandID: "cmd-plan-end",
+		PlanID:    "plan-cmd-plan",
+		EndedAt:   "2026-02-14T08:00:00Z",
+	}))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	notes := "extend by a week"
+	cases := []struct {
+		name string
+		cmd  Command
+		code string
+	}{
+		{
+			name: "update ended plan",
+			cmd: UpdateTreatmentPlan{
+				CommandID: "cmd-plan-update",
+				PlanID:    "plan-cmd-plan",
+				Notes:     &notes,
+			},
+			code: CodeTreatmentPlanEnded,
+		},
+		{
+			name: "end ended plan",
+			cmd: EndTreatmentPlan{
+				CommandID: "cmd-plan-end-again",
+				PlanID:    "plan-cmd-plan",
+				EndedAt:   "2026-02-15T08:00:00Z",
+			},
+			code: CodeTreatmentPlanEnded,
+		},
+		{
+			name: "schedule dose into ended plan",
+			cmd: ScheduleCareItem{
+				CommandID: "cmd-dose",
+				Kind:      CareItemKindTreatmentStep,
+				Title:     "Dose 29",
+				DueAt:     "2026-02-15T08:00:00Z",
+				PlanID:    "plan-cmd-plan",
+			},
+			code: CodeTreatmentPlanEnded,
+		},
+		{
+			name: "update unknown plan",
+			cmd: UpdateTreatmentPlan{
+				CommandID: "cmd-plan-update-unknown",
+				PlanID:    "plan-missing",
+				Notes:     &notes,
+			},
+			code: CodeUnknownTreatmentPlan,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestTreatmentPlanDatesGivenReferenceTimeWhenOutsideDateWindowThenRejectsImplausibleDate(t *testing.T) {
+	reference := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	aggregate, err := LoadFrom(treatmentPlanEvents(), WithReferenceTime(reference))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		cmd   Command
+		field string
+	}{
+		{name: "started before the date window", cmd: StartTreatmentPlan{CommandID: "cmd-2", Title: "Ear drops", StartedAt: "1900-01-01T00:00:00Z"}, field: "started_at"},
+		{name: "ended in the future", cmd: EndTreatmentPlan{CommandID: "cmd-2", PlanID: "plan-cmd-plan", EndedAt: "2999-01-01T00:00:00Z"}, field: "ended_at"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			rejection, ok := err.(Rejection)
+			if !ok || rejection.Code != CodeImplausibleDate || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %v", CodeImplausibleDate, tc.field, err)
+			}
+		})
+	}
+
+	if _, err := aggregate.Decide(StartTreatmentPlan{CommandID: "cmd-3", Title: "Dental course", StartedAt: "2026-04-01T08:00:00Z"}); err != nil {
+		t.Fatalf("expected a plan starting next month to be accepted, got %v", err)
+	}
+}
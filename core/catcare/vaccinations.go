@@ -0,0 +1,102 @@
+package catcare
+
+import (
+	"strings"
+	"time"
+)
+
+// RecordVaccination records an administered vaccine. AdministeredAt is an
+// RFC3339 timestamp; ValidUntil is the last calendar day (YYYY-MM-DD) the
+// vaccine is considered current. ClinicRef is an opaque adapter reference.
+type RecordVaccination struct {
+	CommandID      string
+	VaccineType    string
+	Manufacturer   string
+	LotNumber      string
+	AdministeredAt string
+	ValidUntil     string
+	ClinicRef      string
+}
+
+func (c RecordVaccination) commandName() string { return "RecordVaccination" }
+func (c RecordVaccination) commandID() string   { return c.CommandID }
+
+type VaccinationRecorded struct {
+	CommandID      string
+	VaccinationID  string
+	VaccineType    string
+	Manufacturer   string
+	LotNumber      string
+	AdministeredAt string
+	ValidUntil     string
+	ClinicRef      string
+}
+
+func (e VaccinationRecorded) eventName() string { return "VaccinationRecorded" }
+func (e VaccinationRecorded) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideRecordVaccination(cmd RecordVaccination) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	vaccineType, err := a.requiredText("vaccine_type", cmd.VaccineType, CodeInvalidVaccineType)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	manufacturer, err := a.text("manufacturer", cmd.Manufacturer)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	lotNumber, err := a.requiredText("lot_number", cmd.LotNumber, CodeInvalidLotNumber)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	clinicRef, err := a.text("clinic_ref", cmd.ClinicRef)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+
+	administeredAt := strings.TrimSpace(cmd.AdministeredAt)
+	administered, err := a.plausibleTimestamp("administered_at", administeredAt)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	administeredOn := ""
+	if err == nil {
+		administeredOn = administered.Format(time.DateOnly)
+	}
+	if administeredOn != "" && a.BirthDate != "" && administeredOn < a.BirthDate {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the birth date", Field: "administered_at"}) {
+			return nil, v.err()
+		}
+	}
+
+	validUntil := strings.TrimSpace(cmd.ValidUntil)
+	if validUntil == "" {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be empty", Field: "valid_until"}) {
+			return nil, v.err()
+		}
+	} else if _, err := parseCalendarDate("valid_until", validUntil); v.fail(err) {
+		return nil, v.err()
+	} else if err == nil && administeredOn != "" && validUntil < administeredOn {
+		if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the administration date", Field: "valid_until"}) {
+			return nil, v.err()
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := VaccinationRecorded{
+		CommandID:      cmd.CommandID,
+		VaccinationID:  mintID("vaccination", cmd.CommandID),
+		VaccineType:    vaccineType,
+		Manufacturer:   manufacturer,
+		LotNumber:      lotNumber,
+		AdministeredAt: administeredAt,
+		ValidUntil:     validUntil,
+		ClinicRef:      clinicRef,
+	}
+	return []Event{event}, nil
+}
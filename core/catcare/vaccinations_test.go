@@ -0,0 +1,109 @@
+package catcare
+
+import "testing"
+
+func bornCatEvents() []Event {
+	return []Event{
+		CatRegistered{
+			CommandID: "cmd-register",
+			CatID:     "cat-cmd-register",
+			Name:      "Miso",
+			BirthDate: "2023-01-01",
+		},
+	}
+}
+
+func TestRecordVaccinationGivenRegisteredCatWhenRecordThenEmitsVaccinationRecorded(t *testing.T) {
+	aggregate, err := LoadFrom(bornCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(RecordVaccination{
+		CommandID:      "cmd-vaccine",
+		VaccineType:    "rabies",
+		Manufacturer:   "Zoetis",
+		LotNumber:      " RB-2026-07 ",
+		AdministeredAt: "2026-02-01T10:00:00Z",
+		ValidUntil:     "2027-02-01",
+		ClinicRef:      "clinic-7",
+	})
+	if err != nil {
+		t.Fatalf("decide record vaccination: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event, ok := events[0].(VaccinationRecorded)
+	if !ok {
+		t.Fatalf("expected VaccinationRecorded, got %T", events[0])
+	}
+	if event.VaccinationID != "vaccination-cmd-vaccine" || event.LotNumber != "RB-2026-07" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
+
+func TestRecordVaccinationGivenRegisteredCatWhenRecordInvalidThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(bornCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	valid := RecordVaccination{
+		CommandID:      "cmd-vaccine",
+		VaccineType:    "rabies",
+		LotNumber:      "RB-2026-07",
+		AdministeredAt: "2026-02-01T10:00:00Z",
+		ValidUntil:     "2027-02-01",
+	}
+	cases := []struct {
+		name   string
+		mutate func(*RecordVaccination)
+		code   string
+		field  string
+	}{
+		{
+			name:   "administered before birth",
+			mutate: func(c *RecordVaccination) { c.AdministeredAt = "2022-12-31T10:00:00Z"; c.ValidUntil = "2023-12-31" },
+			code:   CodeInvalidDate,
+			field:  "administered_at",
+		},
+		{
+			name:   "valid until before administration",
+			mutate: func(c *RecordVaccination) { c.ValidUntil = "2026-01-31" },
+			code:   CodeInvalidDate,
+			field:  "valid_until",
+		},
+		{
+			name:   "missing lot number",
+			mutate: func(c *RecordVaccination) { c.LotNumber = " " },
+			code:   CodeInvalidLotNumber,
+			field:  "lot_number",
+		},
+		{
+			name:   "missing vaccine type",
+			mutate: func(c *RecordVaccination) { c.VaccineType = "" },
+			code:   CodeInvalidVaccineType,
+			field:  "vaccine_type",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := valid
+			tc.mutate(&cmd)
+
+			_, err := aggregate.Decide(cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %q on %q", tc.code, tc.field, rejection.Code, rejection.Field)
+			}
+		})
+	}
+}
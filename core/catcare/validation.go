@@ -0,0 +1,87 @@
+package catcare
+
+import "strings"
+
+// Rejections is the composite error Decide returns when WithAllRejections is
+// set and a command fails more than one field-level check.
+type Rejections []Rejection
+
+func (r Rejections) Error() string {
+	messages := make([]string, 0, len(r))
+	for _, rejection := range r {
+		messages = append(messages, rejection.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// WithAllRejections makes Decide report every field-level rejection of a
+// command instead of stopping at the first one, so a caller can correct all
+// fields in one round trip. Checks that do not name a field (e.g. an
+// unregistered cat) and NeedsClarification still stop the decision
+// immediately.
+func WithAllRejections() Option {
+	return func(a *CatCare) {
+		a.allRejections = true
+	}
+}
+
+// validation accumulates the rejections of one decision.
+type validation struct {
+	all        bool
+	rejections []Rejection
+	fatal      error
+}
+
+func (a *CatCare) validation() *validation {
+	return &validation{all: a.allRejections}
+}
+
+// fail records err and reports whether the decision must stop now: on any
+// error that is not a field-level Rejection, and on the first rejection
+// unless all rejections are collected.
+func (v *validation) fail(err error) bool {
+	if err == nil {
+		return false
+	}
+	rejections, ok := AsRejections(err)
+	if !ok {
+		v.fatal = err
+		return true
+	}
+	v.rejections = append(v.rejections, rejections...)
+	for _, rejection := range rejections {
+		if rejection.Field == "" {
+			return true
+		}
+	}
+	return !v.all
+}
+
+// err returns nil, the single rejection, or Rejections when several were
+// collected.
+func (v *validation) err() error {
+	if v.fatal != nil {
+		return v.fatal
+	}
+	switch len(v.rejections) {
+	case 0:
+		return nil
+	case 1:
+		return v.rejections[0]
+	default:
+		return append(Rejections(nil), v.rejections...)
+	}
+}
+
+// AsRejections flattens a Decide error into its rejections. It returns false
+// for errors that are not rejections.
+func AsRejections(err error) ([]Rejection, bool) {
+	switch typed := err.(type) {
+	case Rejection:
+		return []Rejection{typed}, true
+	case Rejections:
+		return append([]Rejection(nil), typed...), true
+	default:
+		return nil, false
+	}
+}
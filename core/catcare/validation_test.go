@@ -0,0 +1,192 @@
+package catcare
+
+import "testing"
+
+func TestDecideGivenAllRejectionsWhenSeveralFieldsInvalidThenReturnsEveryRejection(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithAllRejections())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(PrescribeMedication{
+		CommandID:     "cmd-prescribe",
+		Dose:          -1,
+		Unit:          "mg",
+		Route:         "NASAL",
+		IntervalHours: 12,
+		StartsAt:      "2026-02-01T08:00:00Z",
+		EndsAt:        "2026-01-01T08:00:00Z",
+	})
+	rejections, ok := err.(Rejections)
+	if !ok {
+		t.Fatalf("expected Rejections, got %T (%v)", err, err)
+	}
+	expected := []struct{ code, field string }{
+		{CodeInvalidDrugName, "drug_name"},
+		{CodeInvalidDose, "dose"},
+		{CodeInvalidRoute, "route"},
+		{CodeInvalidDate, "ends_at"},
+	}
+	if len(rejections) != len(expected) {
+		t.Fatalf("expected %d rejections, got %+v", len(expected), rejections)
+	}
+	for index, want := range expected {
+		if rejections[index].Code != want.code || rejections[index].Field != want.field {
+			t.Fatalf("rejection %d = %+v, want %s on %s", index, rejections[index], want.code, want.field)
+		}
+	}
+}
+
+func TestDecideGivenAllRejectionsWhenOneFieldInvalidThenReturnsPlainRejection(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithAllRejections())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(LogWeight{CommandID: "cmd-weight", At: "2026-02-10T08:00:00Z", Grams: 0})
+	rejection, ok := err.(Rejection)
+	if !ok {
+		t.Fatalf("expected Rejection, got %T", err)
+	}
+	if rejection.Code != CodeInvalidWeight {
+		t.Fatalf("expected %q, got %q", CodeInvalidWeight, rejection.Code)
+	}
+}
+
+func TestDecideGivenDefaultModeWhenSeveralFieldsInvalidThenStopsAtFirst(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(LogWeight{CommandID: "cmd-weight", At: "", Grams: 0})
+	rejection, ok := err.(Rejection)
+	if !ok {
+		t.Fatalf("expected Rejection, got %T", err)
+	}
+	if rejection.Field != "at" {
+		t.Fatalf("expected first rejection on at, got %+v", rejection)
+	}
+}
+
+func TestDecideGivenAllRejectionsWhenFollowUpsInvalidThenPrefixesNestedFields(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithAllRejections())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(RecordVetVisit{
+		CommandID: "cmd-visit",
+		VisitedAt: "2026-02-10T09:00:00Z",
+		FollowUps: []VetFollowUp{{Kind: "GROOMING", Title: "", DueAt: "2026-02-20T09:00:00Z"}},
+	})
+	rejections, ok := err.(Rejections)
+	if !ok {
+		t.Fatalf("expected Rejections, got %T (%v)", err, err)
+	}
+	fields := []string{"clinic", "reason", "follow_ups[0].kind", "follow_ups[0].title"}
+	if len(rejections) != len(fields) {
+		t.Fatalf("expected %d rejections, got %+v", len(fields), rejections)
+	}
+	for index, field := range fields {
+		if rejections[index].Field != field {
+			t.Fatalf("rejection %d field = %q, want %q", index, rejections[index].Field, field)
+		}
+	}
+}
+
+func TestDecideGivenAllRejectionsWhenDateAndTextInvalidThenReportsBoth(t *testing.T) {
+	given := append(registeredCatEvents(),
+		CareItemScheduled{
+			CommandID: "cmd-schedule",
+			ItemID:    "item-cmd-schedule",
+			Kind:      CareItemKindOther,
+			Title:     "Brush",
+			DueAt:     "2026-02-01T08:00:00Z",
+		},
+		AnomalyReported{
+			CommandID: "cmd-anomaly",
+			AnomalyID: "anomaly-cmd-anomaly",
+			At:        "2026-02-01T08:00:00Z",
+			Summary:   "Limping",
+			Severity:  SeverityLow,
+		},
+		TreatmentPlanStarted{
+			CommandID: "cmd-plan",
+			PlanID:    "plan-cmd-plan",
+			Title:     "Amoxicillin course",
+			StartedAt: "2026-02-01T08:00:00Z",
+		},
+	)
+	aggregate, err := LoadFrom(given, WithAllRejections())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		cmd   Command
+		field string
+		text  string
+	}{
+		{name: "mark deceased", cmd: MarkCatDeceased{CommandID: "cmd-2", Notes: "a\x00b"}, field: "died_on", text: "notes"},
+		{name: "complete care item", cmd: CompleteCareItem{CommandID: "cmd-2", ItemID: "item-cmd-schedule", Notes: "a\x00b"}, field: "completed_at", text: "notes"},
+		{name: "resolve anomaly", cmd: ResolveAnomaly{CommandID: "cmd-2", AnomalyID: "anomaly-cmd-anomaly", ResolvedAt: "2026-01-01T08:00:00Z", Notes: "a\x00b"}, field: "resolved_at", text: "notes"},
+		{name: "end treatment plan", cmd: EndTreatmentPlan{CommandID: "cmd-2", PlanID: "plan-cmd-plan", EndedAt: "2026-01-01T08:00:00Z", Outcome: "a\x00b"}, field: "ended_at", text: "outcome"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			rejections, ok := err.(Rejections)
+			if !ok {
+				t.Fatalf("expected Rejections, got %T (%v)", err, err)
+			}
+			if len(rejections) != 2 {
+				t.Fatalf("expected 2 rejections, got %+v", rejections)
+			}
+			if rejections[0].Code != CodeInvalidDate || rejections[0].Field != tc.field {
+				t.Fatalf("expected %q on %q first, got %+v", CodeInvalidDate, tc.field, rejections[0])
+			}
+			if rejections[1].Code != CodeInvalidCharacters || rejections[1].Field != tc.text {
+				t.Fatalf("expected %q on %q second, got %+v", CodeInvalidCharacters, tc.text, rejections[1])
+			}
+		})
+	}
+}
+
+func TestDecideGivenAllRejectionsWhenFollowUpDueAtMalformedThenReportsItOnce(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents(), WithAllRejections())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		dueAt string
+		code  string
+	}{
+		{name: "not RFC3339", dueAt: "2026-02-20", code: CodeInvalidDate},
+		{name: "before the date window", dueAt: "1900-01-01T09:00:00Z", code: CodeImplausibleDate},
+		{name: "before the visit", dueAt: "2026-02-01T09:00:00Z", code: CodeInvalidDate},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(RecordVetVisit{
+				CommandID: "cmd-visit",
+				VisitedAt: "2026-02-10T09:00:00Z",
+				Clinic:    "Downtown Vet",
+				Reason:    "Checkup",
+				FollowUps: []VetFollowUp{{Kind: CareItemKindOther, Title: "Recheck", DueAt: tc.dueAt}},
+			})
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected a single Rejection, got %T (%v)", err, err)
+			}
+			if rejection.Code != tc.code || rejection.Field != "follow_ups[0].due_at" {
+				t.Fatalf("expected %q on follow_ups[0].due_at, got %+v", tc.code, rejection)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package catcare
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VetFollowUp is a care item the vet asked for during a visit.
+type VetFollowUp struct {
+	Kind  string
+	Title string
+	DueAt string
+	Notes string
+}
+
+// RecordVetVisit records what happened at a visit. Follow-ups are scheduled
+// as care items in the same decision. AppointmentItemID optionally names the
+// scheduled VET_APPOINTMENT item this visit fulfils; it is completed and
+// linked to the visit. AttachmentIDs reference documents (lab results,
+// invoices, ...) minted by the adapter's attachment registry.
+type RecordVetVisit struct {
+	CommandID         string
+	VisitedAt         string
+	Clinic            string
+	VetName           string
+	Reason            string
+	DiagnosisCodes    []string
+	Notes             string
+	FollowUps         []VetFollowUp
+	AppointmentItemID string
+	AttachmentIDs     []string
+}
+
+func (c RecordVetVisit) commandName() string { return "RecordVetVisit" }
+func (c RecordVetVisit) commandID() string   { return c.CommandID }
+
+type VetVisitRecorded struct {
+	CommandID         string
+	VisitID           string
+	VisitedAt         string
+	Clinic            string
+	VetName           string
+	Reason            string
+	DiagnosisCodes    []string
+	Notes             string
+	FollowUpItemIDs   []string
+	AppointmentItemID string
+	AttachmentIDs     []string
+}
+
+func (e VetVisitRecorded) eventName() string { return "VetVisitRecorded" }
+func (e VetVisitRecorded) commandID() string { return e.CommandID }
+
+// decideRecordVetVisit emits VetVisitRecorded, then one CareItemScheduled per
+// follow-up (item ids "followup-<command_id>-<n>", which no ScheduleCareItem
+// can mint), then the completion of the
+// appointment item if one was named. Any invalid follow-up rejects the whole
+// visit.
+func (a *CatCare) decideRecordVetVisit(cmd RecordVetVisit) ([]Event, error) {
+	if !a.Registered {
+		return nil, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	v := a.validation()
+	visitedAt := strings.TrimSpace(cmd.VisitedAt)
+	visited, visitedErr := a.plausibleTimestamp("visited_at", visitedAt)
+	if v.fail(visitedErr) {
+		return nil, v.err()
+	}
+	clinic, err := a.requiredText("clinic", cmd.Clinic, CodeInvalidClinic)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	vetName, err := a.text("vet_name", cmd.VetName)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	reason, err := a.requiredText("reason", cmd.Reason, CodeInvalidReason)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	diagnosisCodes, err := a.textList("diagnosis_codes", cmd.DiagnosisCodes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	attachmentIDs, err := a.attachmentIDs("attachment_ids", cmd.AttachmentIDs)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+
+	var appointment CareItem
+	appointmentItemID := strings.TrimSpace(cmd.AppointmentItemID)
+	if appointmentItemID != "" {
+		item, err := a.openCareItem(appointmentItemID)
+		if v.fail(withFieldPrefix(err, "appointment_")) {
+			return nil, v.err()
+		}
+		if err == nil && item.Kind != CareItemKindVetAppointment {
+			if v.fail(Rejection{Code: CodeNotVetAppointment, Message: "only VET_APPOINTMENT items link to a visit", Field: "appointment_item_id"}) {
+				return nil, v.err()
+			}
+		}
+		appointment = item
+	}
+
+	visitID := mintID("visit", cmd.CommandID)
+	followUps := make([]Event, 0, len(cmd.FollowUps))
+	followUpItemIDs := make([]string, 0, len(cmd.FollowUps))
+	for index, followUp := range cmd.FollowUps {
+		prefix := "follow_ups[" + strconv.Itoa(index) + "]."
+		dueAt := strings.TrimSpace(followUp.DueAt)
+		itemCommandID := cmd.CommandID + "-" + strconv.Itoa(index+1)
+		decided, err := a.decideScheduleCareItem(ScheduleCareItem{
+			CommandID: itemCommandID,
+			Kind:      followUp.Kind,
+			Title:     followUp.Title,
+			DueAt:     dueAt,
+			Notes:     followUp.Notes,
+		})
+		if v.fail(withFieldPrefix(err, prefix)) {
+			return nil, v.err()
+		}
+		// The nested decision already parsed and reported due_at; only a
+		// due_at it accepted is compared with the visit.
+		if !rejectsField(err, "due_at") && visitedErr == nil {
+			if due, parseErr := time.Parse(time.RFC3339, dueAt); parseErr == nil && due.Before(visited) {
+				if v.fail(Rejection{Code: CodeInvalidDate, Message: "must not be before the visit", Field: prefix + "due_at"}) {
+					return nil, v.err()
+				}
+				continue
+			}
+		}
+		if err != nil {
+			continue
+		}
+		scheduled := decided[0].(CareItemScheduled)
+		scheduled.CommandID = cmd.CommandID
+		scheduled.ItemID = mintID("followup", itemCommandID)
+		scheduled.FollowUpOfVisitID = visitID
+		followUps = append(followUps, scheduled)
+		followUpItemIDs = append(followUpItemIDs, scheduled.ItemID)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if len(followUpItemIDs) == 0 {
+		followUpItemIDs = nil
+	}
+
+	events := []Event{VetVisitRecorded{
+		CommandID:         cmd.CommandID,
+		VisitID:           visitID,
+		VisitedAt:         visitedAt,
+		Clinic:            clinic,
+		VetName:           vetName,
+		Reason:            reason,
+		DiagnosisCodes:    diagnosisCodes,
+		Notes:             notes,
+		FollowUpItemIDs:   followUpItemIDs,
+		AppointmentItemID: appointmentItemID,
+		AttachmentIDs:     attachmentIDs,
+	}}
+	events = append(events, followUps...)
+	if appointmentItemID != "" {
+		completed, err := completeCareItem(appointment, cmd.CommandID, visitedAt, "", visitID)
+		if err != nil {
+			return nil, withFieldPrefix(err, "appointment_")
+		}
+		events = append(events, completed...)
+	}
+	return events, nil
+}
+
+// rejectsField reports whether err holds a rejection of field.
+func rejectsField(err error, field string) bool {
+	rejections, _ := AsRejections(err)
+	for _, rejection := range rejections {
+		if rejection.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// withFieldPrefix qualifies the fields of rejections (or a clarification)
+// raised by a nested validation so callers can tell which part of the command was wrong.
+func withFieldPrefix(err error, prefix string) error {
+	if clarification, ok := AsClarification(err); ok {
+		clarification.Field = prefix + clarification.Field
+		return clarification
+	}
+	rejections, ok := AsRejections(err)
+	if !ok {
+		return err
+	}
+	for index := range rejections {
+		if rejections[index].Field != "" {
+			rejections[index].Field = prefix + rejections[index].Field
+		}
+	}
+	if len(rejections) == 1 {
+		return rejections[0]
+	}
+	return Rejections(rejections)
+}
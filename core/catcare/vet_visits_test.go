@@ -0,0 +1,237 @@
+package catcare
+
+import "testing"
+
+func appointmentEvents() []Event {
+	return append(registeredCatEvents(),
+		CareItemScheduled{
+			CommandID: "cmd-appointment",
+			ItemID:    "item-cmd-appointment",
+			Kind:      CareItemKindVetAppointment,
+			Title:     "Annual check-up",
+			DueAt:     "2026-02-10T09:00:00Z",
+		},
+		CareItemScheduled{
+			CommandID: "cmd-vaccine",
+			ItemID:    "item-cmd-vaccine",
+			Kind:      CareItemKindVaccine,
+			Title:     "Rabies",
+			DueAt:     "2026-02-10T09:00:00Z",
+		},
+	)
+}
+
+func TestRecordVetVisitGivenAppointmentWhenRecordWithFollowUpsThenSchedulesAndLinksAtomically(t *testing.T) {
+	aggregate, err := LoadFrom(appointmentEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(RecordVetVisit{
+		CommandID:      "cmd-visit",
+		VisitedAt:      "2026-02-10T09:20:00Z",
+		Clinic:         "Downtown Vet",
+		VetName:        "Dr. Ana",
+		Reason:         "Annual check-up",
+		DiagnosisCodes: []string{" K08.1 ", "", "K08.1", "E66"},
+		FollowUps: []VetFollowUp{
+			{Kind: CareItemKindVetAppointment, Title: "Dental recheck", DueAt: "2026-03-10T09:00:00Z"},
+			{Kind: CareItemKindTreatmentStep, Title: "Start dental diet", DueAt: "2026-02-11T08:00:00Z"},
+		},
+		AppointmentItemID: "item-cmd-appointment",
+	})
+	if err != nil {
+		t.Fatalf("decide record vet visit: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected visit, 2 follow-ups and completion, got %d events", len(events))
+	}
+
+	visit, ok := events[0].(VetVisitRecorded)
+	if !ok {
+		t.Fatalf("expected VetVisitRecorded, got %T", events[0])
+	}
+	if visit.VisitID != "visit-cmd-visit" {
+		t.Fatalf("expected deterministic visit id, got %q", visit.VisitID)
+	}
+	if len(visit.DiagnosisCodes) != 2 || visit.DiagnosisCodes[0] != "K08.1" || visit.DiagnosisCodes[1] != "E66" {
+		t.Fatalf("expected normalized diagnosis codes, got %v", visit.DiagnosisCodes)
+	}
+	for index, itemID := range []string{"followup-cmd-visit-1", "followup-cmd-visit-2"} {
+		scheduled, ok := events[index+1].(CareItemScheduled)
+		if !ok {
+			t.Fatalf("expected CareItemScheduled, got %T", events[index+1])
+		}
+		if scheduled.ItemID != itemID || scheduled.FollowUpOfVisitID != visit.VisitID || scheduled.CommandID != "cmd-visit" {
+			t.Fatalf("unexpected follow-up %+v", scheduled)
+		}
+		if visit.FollowUpItemIDs[index] != itemID {
+			t.Fatalf("visit follow-up ids = %v", visit.FollowUpItemIDs)
+		}
+	}
+	completed, ok := events[3].(CareItemCompleted)
+	if !ok {
+		t.Fatalf("expected CareItemCompleted, got %T", events[3])
+	}
+	if completed.ItemID != "item-cmd-appointment" || completed.VisitID != visit.VisitID {
+		t.Fatalf("unexpected completion %+v", completed)
+	}
+
+	for _, event := range events {
+		if err := aggregate.Apply(event); err != nil {
+			t.Fatalf("apply event: %v", err)
+		}
+	}
+	if aggregate.CareItems["item-cmd-appointment"].VisitID != visit.VisitID {
+		t.Fatalf("expected appointment linked to visit, got %+v", aggregate.CareItems["item-cmd-appointment"])
+	}
+	if _, exists := aggregate.VetVisits[visit.VisitID]; !exists {
+		t.Fatal("expected visit in state")
+	}
+}
+
+func TestScheduleCareItemGivenVisitFollowUpWhenCommandIDEndsLikeFollowUpThenKeepsBothItems(t *testing.T) {
+	aggregate, err := LoadFrom(appointmentEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	commands := []Command{
+		RecordVetVisit{
+			CommandID: "cmd-visit",
+			VisitedAt: "2026-02-10T09:20:00Z",
+			Clinic:    "Downtown Vet",
+			Reason:    "Annual check-up",
+			FollowUps: []VetFollowUp{{Kind: CareItemKindVetAppointment, Title: "Dental recheck", DueAt: "2026-03-10T09:00:00Z"}},
+		},
+		ScheduleCareItem{CommandID: "cmd-visit-1", Kind: CareItemKindOther, Title: "Buy litter", DueAt: "2026-02-12T09:00:00Z"},
+	}
+	for _, command := range commands {
+		events, err := aggregate.Decide(command)
+		if err != nil {
+			t.Fatalf("decide %T: %v", command, err)
+		}
+		for _, event := range events {
+			if err := aggregate.Apply(event); err != nil {
+				t.Fatalf("apply %T: %v", event, err)
+			}
+		}
+	}
+
+	if followUp := aggregate.CareItems["followup-cmd-visit-1"]; followUp.Title != "Dental recheck" {
+		t.Fatalf("expected the follow-up to survive, got %+v", followUp)
+	}
+	if scheduled := aggregate.CareItems["item-cmd-visit-1"]; scheduled.Title != "Buy litter" {
+		t.Fatalf("expected the scheduled item, got %+v", scheduled)
+	}
+}
+
+func TestRecordVetVisitGivenAppointmentWhenInvalidThenRejectsWholeVisit(t *testing.T) {
+	aggregate, err := LoadFrom(appointmentEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	valid := RecordVetVisit{
+		CommandID: "cmd-visit",
+		VisitedAt: "2026-02-10T09:20:00Z",
+		Clinic:    "Downtown Vet",
+		Reason:    "Check-up",
+	}
+	cases := []struct {
+		name   string
+		mutate func(*RecordVetVisit)
+		code   string
+		field  string
+	}{
+		{name: "missing clinic", mutate: func(c *RecordVetVisit) { c.Clinic = "" }, code: CodeInvalidClinic, field: "clinic"},
+		{name: "missing reason", mutate: func(c *RecordVetVisit) { c.Reason = " " }, code: CodeInvalidReason, field: "reason"},
+		{name: "bad visit time", mutate: func(c *RecordVetVisit) { c.VisitedAt = "2026-02-10" }, code: CodeInvalidDate, field: "visited_at"},
+		{
+			name: "follow-up kind",
+			mutate: func(c *RecordVetVisit) {
+				c.FollowUps = []VetFollowUp{{Kind: CareItemKindOther, Title: "ok", DueAt: "2026-02-20T09:00:00Z"}, {Kind: "GROOMING", Title: "Bath", DueAt: "2026-02-20T09:00:00Z"}}
+			},
+			code:  CodeInvalidKind,
+			field: "follow_ups[1].kind",
+		},
+		{
+			name: "follow-up before visit",
+			mutate: func(c *RecordVetVisit) {
+				c.FollowUps = []VetFollowUp{{Kind: CareItemKindOther, Title: "Recheck", DueAt: "2026-02-01T09:00:00Z"}}
+			},
+			code:  CodeInvalidDate,
+			field: "follow_ups[0].due_at",
+		},
+		{name: "unknown appointment", mutate: func(c *RecordVetVisit) { c.AppointmentItemID = "item-missing" }, code: CodeUnknownCareItem, field: "appointment_item_id"},
+		{name: "not an appointment", mutate: func(c *RecordVetVisit) { c.AppointmentItemID = "item-cmd-vaccine" }, code: CodeNotVetAppointment, field: "appointment_item_id"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := valid
+			tc.mutate(&cmd)
+
+			_, err := aggregate.Decide(cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %q on %q", tc.code, tc.field, rejection.Code, rejection.Field)
+			}
+		})
+	}
+}
+
+func TestCompleteCareItemGivenRecordedVisitWhenCompleteWithVisitIDThenLinksVisit(t *testing.T) {
+	events := append(appointmentEvents(), VetVisitRecorded{
+		CommandID: "cmd-visit",
+		VisitID:   "visit-cmd-visit",
+		VisitedAt: "2026-02-10T09:20:00Z",
+		Clinic:    "Downtown Vet",
+		Reason:    "Check-up",
+	})
+	aggregate, err := LoadFrom(events)
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	decided, err := aggregate.Decide(CompleteCareItem{
+		CommandID:   "cmd-complete",
+		ItemID:      "item-cmd-appointment",
+		CompletedAt: "2026-02-10T10:00:00Z",
+		VisitID:     "visit-cmd-visit",
+	})
+	if err != nil {
+		t.Fatalf("decide complete care item: %v", err)
+	}
+	if completed := decided[0].(CareItemCompleted); completed.VisitID != "visit-cmd-visit" {
+		t.Fatalf("expected visit link, got %+v", completed)
+	}
+
+	_, err = aggregate.Decide(CompleteCareItem{
+		CommandID:   "cmd-complete-vaccine",
+		ItemID:      "item-cmd-vaccine",
+		CompletedAt: "2026-02-10T10:00:00Z",
+		VisitID:     "visit-cmd-visit",
+	})
+	rejection, ok := err.(Rejection)
+	if !ok || rejection.Code != CodeNotVetAppointment {
+		t.Fatalf("expected %q, got %v", CodeNotVetAppointment, err)
+	}
+
+	_, err = aggregate.Decide(CompleteCareItem{
+		CommandID:   "cmd-complete-unknown",
+		ItemID:      "item-cmd-appointment",
+		CompletedAt: "2026-02-10T10:00:00Z",
+		VisitID:     "visit-missing",
+	})
+	rejection, ok = err.(Rejection)
+	if !ok || rejection.Code != CodeUnknownVetVisit {
+		t.Fatalf("expected %q, got %v", CodeUnknownVetVisit, err)
+	}
+}
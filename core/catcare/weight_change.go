@@ -0,0 +1,104 @@
+package catcare
+
+import (
+	"fmt"
+	"time"
+)
+
+// WeightChangePolicy decides when LogWeight also reports an anomaly. A change
+// of at least ThresholdPercent against the previous entry, measured within
+// Window, is flagged; HighPercent and CriticalPercent raise the severity.
+// A zero ThresholdPercent or Window disables flagging.
+type WeightChangePolicy struct {
+	ThresholdPercent int
+	HighPercent      int
+	CriticalPercent  int
+	Window           time.Duration
+}
+
+// DefaultWeightChangePolicy returns the policy New uses when
+// WithWeightChangePolicy is not given.
+func DefaultWeightChangePolicy() WeightChangePolicy {
+	return WeightChangePolicy{
+		ThresholdPercent: 10,
+		HighPercent:      15,
+		CriticalPercent:  20,
+		Window:           30 * 24 * time.Hour,
+	}
+}
+
+const (
+	TagWeight     = "weight"
+	TagWeightLoss = "weight_loss"
+	TagWeightGain = "weight_gain"
+)
+
+func WithWeightChangePolicy(policy WeightChangePolicy) Option {
+	return func(a *CatCare) {
+		a.weightChangePolicy = policy
+	}
+}
+
+// weightChangeAnomaly compares a new measurement with the latest entry taken
+// at or before it and returns the anomaly to emit, if any.
+func (a *CatCare) weightChangeAnomaly(logged WeightLogged, measuredAt time.Time) (AnomalyReported, bool) {
+	policy := a.weightChangePolicy
+	if policy.ThresholdPercent <= 0 || policy.Window <= 0 {
+		return AnomalyReported{}, false
+	}
+
+	previous, previousAt, found := a.previousWeightEntry(measuredAt)
+	if !found || measuredAt.Sub(previousAt) > policy.Window {
+		return AnomalyReported{}, false
+	}
+
+	delta := logged.Grams - previous.Grams
+	magnitude := delta
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude*100 < policy.ThresholdPercent*previous.Grams {
+		return AnomalyReported{}, false
+	}
+
+	severity := SeverityMedium
+	switch {
+	case policy.CriticalPercent > 0 && magnitude*100 >= policy.CriticalPercent*previous.Grams:
+		severity = SeverityCritical
+	case policy.HighPercent > 0 && magnitude*100 >= policy.HighPercent*previous.Grams:
+		severity = SeverityHigh
+	}
+
+	direction, tag := "lost", TagWeightLoss
+	if delta > 0 {
+		direction, tag = "gained", TagWeightGain
+	}
+
+	return AnomalyReported{
+		CommandID:     logged.CommandID,
+		AnomalyID:     mintID("anomaly", logged.CommandID),
+		At:            logged.At,
+		Summary:       fmt.Sprintf("%s %d%% of body weight (%dg to %dg) since %s", direction, magnitude*100/previous.Grams, previous.Grams, logged.Grams, previous.At),
+		Severity:      severity,
+		Tags:          []string{TagWeight, tag},
+		WeightEntryID: logged.EntryID,
+	}, true
+}
+
+func (a *CatCare) previousWeightEntry(measuredAt time.Time) (WeightLogged, time.Time, bool) {
+	var (
+		previous   WeightLogged
+		previousAt time.Time
+		found      bool
+	)
+	for _, entry := range a.WeightEntries {
+		entryAt, err := time.Parse(time.RFC3339, entry.At)
+		if err != nil || entryAt.After(measuredAt) {
+			continue
+		}
+		if !found || !entryAt.Before(previousAt) {
+			previous, previousAt, found = entry, entryAt, true
+		}
+	}
+	return previous, previousAt, found
+}
@@ -0,0 +1,113 @@
+package catcare
+
+import (
+	"testing"
+	"time"
+)
+
+func previousWeightEvents() []Event {
+	return append(registeredCatEvents(), WeightLogged{
+		CommandID: "cmd-weight-1",
+		EntryID:   "weight-cmd-weight-1",
+		At:        "2026-02-01T10:00:00Z",
+		Grams:     4000,
+	})
+}
+
+func TestLogWeightGivenPreviousEntryWhenChangeExceedsThresholdThenAlsoReportsAnomaly(t *testing.T) {
+	cases := []struct {
+		name     string
+		grams    int
+		severity string
+		tag      string
+	}{
+		{name: "ten percent loss", grams: 3600, severity: SeverityMedium, tag: TagWeightLoss},
+		{name: "fifteen percent gain", grams: 4600, severity: SeverityHigh, tag: TagWeightGain},
+		{name: "twenty percent loss", grams: 3200, severity: SeverityCritical, tag: TagWeightLoss},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(previousWeightEvents())
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			events, err := aggregate.Decide(LogWeight{
+				CommandID: "cmd-weight-2",
+				At:        "2026-02-14T10:00:00Z",
+				Grams:     tc.grams,
+			})
+			if err != nil {
+				t.Fatalf("decide log weight: %v", err)
+			}
+			if len(events) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(events))
+			}
+			if _, ok := events[0].(WeightLogged); !ok {
+				t.Fatalf("expected WeightLogged first, got %T", events[0])
+			}
+			anomaly, ok := events[1].(AnomalyReported)
+			if !ok {
+				t.Fatalf("expected AnomalyReported second, got %T", events[1])
+			}
+			if anomaly.Severity != tc.severity {
+				t.Fatalf("expected severity %q, got %q", tc.severity, anomaly.Severity)
+			}
+			if anomaly.AnomalyID != "anomaly-cmd-weight-2" || anomaly.WeightEntryID != "weight-cmd-weight-2" {
+				t.Fatalf("unexpected anomaly ids %+v", anomaly)
+			}
+			if len(anomaly.Tags) != 2 || anomaly.Tags[1] != tc.tag {
+				t.Fatalf("unexpected tags %v", anomaly.Tags)
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenPreviousEntryWhenChangeNotFlaggedThenOnlyLogsWeight(t *testing.T) {
+	cases := []struct {
+		name    string
+		at      string
+		grams   int
+		options []Option
+	}{
+		{name: "below threshold", at: "2026-02-14T10:00:00Z", grams: 3650},
+		{name: "outside window", at: "2026-03-14T10:00:00Z", grams: 3000},
+		{
+			name:    "disabled policy",
+			at:      "2026-02-14T10:00:00Z",
+			grams:   3000,
+			options: []Option{WithWeightChangePolicy(WeightChangePolicy{})},
+		},
+		{
+			name:  "custom threshold",
+			at:    "2026-02-14T10:00:00Z",
+			grams: 3000,
+			options: []Option{WithWeightChangePolicy(WeightChangePolicy{
+				ThresholdPercent: 30,
+				Window:           7 * 24 * time.Hour,
+			})},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregate, err := LoadFrom(previousWeightEvents(), tc.options...)
+			if err != nil {
+				t.Fatalf("load aggregate: %v", err)
+			}
+
+			events, err := aggregate.Decide(LogWeight{
+				CommandID: "cmd-weight-2",
+				At:        tc.at,
+				Grams:     tc.grams,
+			})
+			if err != nil {
+				t.Fatalf("decide log weight: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+		})
+	}
+}
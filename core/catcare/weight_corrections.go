@@ -0,0 +1,177 @@
+package catcare
+
+import "strings"
+
+// CorrectWeightEntry replaces the values of a previously logged entry. An
+// empty At keeps the original measurement time. The weight is given like in
+// LogWeight, as Grams or as Amount with Unit; a zero BodyConditionScore
+// records the score as not assessed.
+type CorrectWeightEntry struct {
+	CommandID          string
+	EntryID            string
+	At                 string
+	Grams              int
+	Amount             string
+	Unit               string
+	BodyConditionScore int
+	Notes              string
+	Reason             string
+}
+
+func (c CorrectWeightEntry) commandName() string { return "CorrectWeightEntry" }
+func (c CorrectWeightEntry) commandID() string   { return c.CommandID }
+
+type RetractWeightEntry struct {
+	CommandID string
+	EntryID   string
+	Reason    string
+}
+
+func (c RetractWeightEntry) commandName() string { return "RetractWeightEntry" }
+func (c RetractWeightEntry) commandID() string   { return c.CommandID }
+
+// WeightEntryCorrected carries the full corrected values; the original
+// WeightLogged stays untouched in the stream.
+type WeightEntryCorrected struct {
+	CommandID          string
+	EntryID            string
+	At                 string
+	Grams              int
+	EnteredAmount      string
+	EnteredUnit        string
+	BodyConditionScore int
+	Notes              string
+	Reason             string
+}
+
+func (e WeightEntryCorrected) eventName() string { return "WeightEntryCorrected" }
+func (e WeightEntryCorrected) commandID() string { return e.CommandID }
+
+type WeightEntryRetracted struct {
+	CommandID string
+	EntryID   string
+	Reason    string
+}
+
+func (e WeightEntryRetracted) eventName() string { return "WeightEntryRetracted" }
+func (e WeightEntryRetracted) commandID() string { return e.CommandID }
+
+func (a *CatCare) decideCorrectWeightEntry(cmd CorrectWeightEntry) ([]Event, error) {
+	entry, err := a.activeWeightEntry(cmd.EntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := a.validation()
+	at := strings.TrimSpace(cmd.At)
+	if at == "" {
+		at = entry.At
+	} else if _, err := a.plausibleTimestamp("at", at); v.fail(err) {
+		return nil, v.err()
+	}
+	grams, err := weightGrams(cmd.Grams, cmd.Amount, cmd.Unit)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if v.fail(checkBodyConditionScore(cmd.BodyConditionScore)) {
+		return nil, v.err()
+	}
+	notes, err := a.text("notes", cmd.Notes)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	reason, err := a.text("reason", cmd.Reason)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := WeightEntryCorrected{
+		CommandID:          cmd.CommandID,
+		EntryID:            entry.EntryID,
+		At:                 at,
+		Grams:              grams,
+		BodyConditionScore: cmd.BodyConditionScore,
+		Notes:              notes,
+		Reason:             reason,
+	}
+	if cmd.Grams == 0 {
+		event.EnteredAmount = strings.TrimSpace(cmd.Amount)
+		event.EnteredUnit = strings.TrimSpace(cmd.Unit)
+	}
+	if sameWeightValues(entry, event) {
+		return nil, Rejection{Code: CodeUnchangedWeightEntry, Message: "correction must change the entry", Field: "entry_id"}
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) decideRetractWeightEntry(cmd RetractWeightEntry) ([]Event, error) {
+	entry, err := a.activeWeightEntry(cmd.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	v := a.validation()
+	reason, err := a.text("reason", cmd.Reason)
+	if v.fail(err) {
+		return nil, v.err()
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	event := WeightEntryRetracted{
+		CommandID: cmd.CommandID,
+		EntryID:   entry.EntryID,
+		Reason:    reason,
+	}
+	return []Event{event}, nil
+}
+
+func (a *CatCare) activeWeightEntry(entryID string) (WeightLogged, error) {
+	if !a.Registered {
+		return WeightLogged{}, Rejection{Code: CodeNotRegistered, Message: "cat must be registered first"}
+	}
+	if strings.TrimSpace(entryID) == "" {
+		return WeightLogged{}, Rejection{Code: CodeInvalidEntryID, Message: "must not be empty", Field: "entry_id"}
+	}
+	if _, retracted := a.retractedEntryIDs[entryID]; retracted {
+		return WeightLogged{}, Rejection{Code: CodeWeightEntryRetracted, Message: "weight entry already retracted", Field: "entry_id"}
+	}
+	index := a.weightEntryIndex(entryID)
+	if index < 0 {
+		return WeightLogged{}, Rejection{Code: CodeUnknownWeightEntry, Message: "weight entry does not exist", Field: "entry_id"}
+	}
+	return a.WeightEntries[index], nil
+}
+
+func (a *CatCare) weightEntryIndex(entryID string) int {
+	for index, entry := range a.WeightEntries {
+		if entry.EntryID == entryID {
+			return index
+		}
+	}
+	return -1
+}
+
+// sameWeightValues reports whether a correction would leave every recorded
+// value of entry as it is, including the entered unit and the body condition
+// score.
+func sameWeightValues(entry WeightLogged, correction WeightEntryCorrected) bool {
+	return correction.At == entry.At &&
+		correction.Grams == entry.Grams &&
+		correction.EnteredAmount == entry.EnteredAmount &&
+		correction.EnteredUnit == entry.EnteredUnit &&
+		correction.BodyConditionScore == entry.BodyConditionScore &&
+		correction.Notes == entry.Notes
+}
+
+// checkBodyConditionScore accepts 0 (not assessed) or a score on the 9-point
+// scale.
+func checkBodyConditionScore(score int) error {
+	if score != 0 && (score < MinBodyConditionScore || score > MaxBodyConditionScore) {
+		return Rejection{Code: CodeInvalidBodyConditionScore, Message: "must be between 1 and 9 when set", Field: "body_condition_score"}
+	}
+	return nil
+}
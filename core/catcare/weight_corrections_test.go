@@ -0,0 +1,184 @@
+package catcare
+
+import "testing"
+
+func loggedWeightEvents() []Event {
+	return append(registeredCatEvents(),
+		WeightLogged{
+			CommandID: "cmd-weight-1",
+			EntryID:   "weight-cmd-weight-1",
+			At:        "2026-02-14T10:00:00Z",
+			Grams:     24000,
+			Notes:     "post breakfast",
+		},
+		WeightLogged{
+			CommandID: "cmd-weight-2",
+			EntryID:   "weight-cmd-weight-2",
+			At:        "2026-02-15T10:00:00Z",
+			Grams:     4150,
+		},
+	)
+}
+
+func TestCorrectWeightEntryGivenLoggedEntryWhenCorrectThenStateShowsCorrection(t *testing.T) {
+	aggregate, err := LoadFrom(loggedWeightEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(CorrectWeightEntry{
+		CommandID: "cmd-correct",
+		EntryID:   "weight-cmd-weight-1",
+		Grams:     4200,
+		Notes:     "post breakfast",
+		Reason:    "typo",
+	})
+	if err != nil {
+		t.Fatalf("decide correct weight entry: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event, ok := events[0].(WeightEntryCorrected)
+	if !ok {
+		t.Fatalf("expected WeightEntryCorrected, got %T", events[0])
+	}
+	if event.At != "2026-02-14T10:00:00Z" {
+		t.Fatalf("expected original time to be kept, got %q", event.At)
+	}
+
+	if err := aggregate.Apply(event); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if len(aggregate.WeightEntries) != 2 || aggregate.WeightEntries[0].Grams != 4200 {
+		t.Fatalf("unexpected weight entries %+v", aggregate.WeightEntries)
+	}
+}
+
+func TestRetractWeightEntryGivenLoggedEntryWhenRetractThenEntryLeavesState(t *testing.T) {
+	aggregate, err := LoadFrom(loggedWeightEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(RetractWeightEntry{
+		CommandID: "cmd-retract",
+		EntryID:   "weight-cmd-weight-1",
+		Reason:    "wrong cat on the scale",
+	})
+	if err != nil {
+		t.Fatalf("decide retract weight entry: %v", err)
+	}
+	if _, ok := events[0].(WeightEntryRetracted); !ok {
+		t.Fatalf("expected WeightEntryRetracted, got %T", events[0])
+	}
+
+	if err := aggregate.Apply(events[0]); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if len(aggregate.WeightEntries) != 1 || aggregate.WeightEntries[0].EntryID != "weight-cmd-weight-2" {
+		t.Fatalf("unexpected weight entries %+v", aggregate.WeightEntries)
+	}
+}
+
+func TestWeightEntryCorrectionGivenUnknownOrRetractedEntryWhenDecideThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(append(loggedWeightEvents(), WeightEntryRetracted{
+		CommandID: "cmd-retract",
+		EntryID:   "weight-cmd-weight-1",
+	}))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  Command
+		code string
+	}{
+		{
+			name: "correct unknown entry",
+			cmd:  CorrectWeightEntry{CommandID: "cmd-correct-unknown", EntryID: "weight-missing", Grams: 4200},
+			code: CodeUnknownWeightEntry,
+		},
+		{
+			name: "correct retracted entry",
+			cmd:  CorrectWeightEntry{CommandID: "cmd-correct-retracted", EntryID: "weight-cmd-weight-1", Grams: 4200},
+			code: CodeWeightEntryRetracted,
+		},
+		{
+			name: "retract retracted entry",
+			cmd:  RetractWeightEntry{CommandID: "cmd-retract-again", EntryID: "weight-cmd-weight-1"},
+			code: CodeWeightEntryRetracted,
+		},
+		{
+			name: "correction without change",
+			cmd:  CorrectWeightEntry{CommandID: "cmd-correct-same", EntryID: "weight-cmd-weight-2", Grams: 4150},
+			code: CodeUnchangedWeightEntry,
+		},
+		{
+			name: "correction with body condition score off the scale",
+			cmd:  CorrectWeightEntry{CommandID: "cmd-correct-bcs", EntryID: "weight-cmd-weight-2", Grams: 4150, BodyConditionScore: 10},
+			code: CodeInvalidBodyConditionScore,
+		},
+		{
+			name: "correction with absurd grams",
+			cmd:  CorrectWeightEntry{CommandID: "cmd-correct-absurd", EntryID: "weight-cmd-weight-2", Grams: MaxWeightGrams + 1},
+			code: CodeAbsurdWeight,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.cmd)
+			if err == nil {
+				t.Fatal("expected rejection")
+			}
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %T", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestCorrectWeightEntryGivenLoggedEntryWhenOnlyScoreOrUnitChangesThenAccepts(t *testing.T) {
+	aggregate, err := LoadFrom(loggedWeightEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		cmd  CorrectWeightEntry
+	}{
+		{name: "body condition score", cmd: CorrectWeightEntry{CommandID: "cmd-correct", EntryID: "weight-cmd-weight-2", Grams: 4150, BodyConditionScore: 5}},
+		{name: "entered unit", cmd: CorrectWeightEntry{CommandID: "cmd-correct", EntryID: "weight-cmd-weight-2", Amount: "4.15", Unit: UnitKilograms}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := aggregate.Decide(tc.cmd)
+			if err != nil {
+				t.Fatalf("decide correct weight entry: %v", err)
+			}
+			corrected := events[0].(WeightEntryCorrected)
+			if corrected.Grams != 4150 || corrected.BodyConditionScore != tc.cmd.BodyConditionScore {
+				t.Fatalf("unexpected correction %+v", corrected)
+			}
+		})
+	}
+
+	events, err := aggregate.Decide(CorrectWeightEntry{CommandID: "cmd-correct", EntryID: "weight-cmd-weight-2", Grams: 4150, BodyConditionScore: 5})
+	if err != nil {
+		t.Fatalf("decide correct weight entry: %v", err)
+	}
+	if err := aggregate.Apply(events[0]); err != nil {
+		t.Fatalf("apply event: %v", err)
+	}
+	if got := aggregate.WeightEntries[1].BodyConditionScore; got != 5 {
+		t.Fatalf("expected corrected body condition score 5, got %d", got)
+	}
+}
@@ -0,0 +1,166 @@
+package catcare
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	UnitGrams     = "g"
+	UnitKilograms = "kg"
+	UnitPounds    = "lb"
+)
+
+// A pound is exactly 0.45359237 kg, i.e. 45359237 / 100000 grams.
+const (
+	gramsPerPoundNumerator   = 45359237
+	gramsPerPoundDenominator = 100000
+)
+
+// maxPoundDecimals is the finest pound precision accepted: 0.01 lb is about
+// 4.5 g, so rounding to the gram never merges two distinct inputs, while
+// 0.001 lb (0.45 g) would be lost.
+const maxPoundDecimals = 2
+
+var decimalAmount = regexp.MustCompile(`^(\d{1,6})(?:\.(\d{1,6}))?$`)
+
+// weightGrams resolves the weight of a command. Grams alone is the legacy
+// form; Amount with Unit converts exactly from g, kg or lb and rejects input
+// more precise than whole grams can keep. An amount in grams, or without a
+// unit, that only makes sense as kg or lb needs clarification instead.
+func weightGrams(grams int, amount string, unit string) (int, error) {
+	amount = strings.TrimSpace(amount)
+	unit = strings.TrimSpace(unit)
+	if amount == "" && unit == "" {
+		return grams, validateGrams(grams)
+	}
+	if grams != 0 {
+		return 0, Rejection{Code: CodeInvalidWeight, Message: "use either grams or amount with unit", Field: "grams"}
+	}
+	match := decimalAmount.FindStringSubmatch(amount)
+	if match != nil && (unit == "" || unit == UnitGrams) {
+		if clarification := misenteredAmountUnit(amount, unit, match); clarification != nil {
+			return 0, clarification
+		}
+	}
+	if unit != UnitGrams && unit != UnitKilograms && unit != UnitPounds {
+		return 0, Rejection{Code: CodeInvalidWeightUnit, Message: "must be one of g, kg, lb", Field: "unit"}
+	}
+	if match == nil {
+		return 0, Rejection{Code: CodeInvalidWeight, Message: "must be a decimal number like 4.25", Field: "amount"}
+	}
+
+	converted, err := convertAmount(match, unit)
+	if err != nil {
+		return 0, err
+	}
+	if converted <= 0 {
+		return 0, Rejection{Code: CodeInvalidWeight, Message: "must be positive", Field: "amount"}
+	}
+	if converted < MinWeightGrams || converted > MaxWeightGrams {
+		return 0, Rejection{Code: CodeAbsurdWeight, Message: "outside allowed range", Field: "amount"}
+	}
+	return converted, nil
+}
+
+// convertAmount converts a decimalAmount match in unit to whole grams, or
+// rejects it as lossy.
+func convertAmount(match []string, unit string) (int, error) {
+	fraction := strings.TrimRight(match[2], "0")
+	scaled, _ := strconv.Atoi(match[1] + fraction)
+	decimals := len(fraction)
+	switch unit {
+	case UnitGrams:
+		if decimals > 0 {
+			return 0, Rejection{Code: CodeLossyWeight, Message: "must be whole grams", Field: "amount"}
+		}
+		return scaled, nil
+	case UnitKilograms:
+		if decimals > 3 {
+			return 0, Rejection{Code: CodeLossyWeight, Message: "must have at most 3 decimals (whole grams)", Field: "amount"}
+		}
+		return scaled * pow10(3-decimals), nil
+	default:
+		if decimals > maxPoundDecimals {
+			return 0, Rejection{Code: CodeLossyWeight, Message: "must have at most 2 decimals", Field: "amount"}
+		}
+		denominator := gramsPerPoundDenominator * pow10(decimals)
+		return (scaled*gramsPerPoundNumerator + denominator/2) / denominator, nil
+	}
+}
+
+func validateGrams(grams int) error {
+	if grams <= 0 {
+		return Rejection{Code: CodeInvalidWeight, Message: "must be positive", Field: "grams"}
+	}
+	if grams < MinWeightGrams || grams > MaxWeightGrams {
+		if clarification := misenteredUnit(grams); clarification != nil {
+			return clarification
+		}
+		return Rejection{Code: CodeAbsurdWeight, Message: "outside allowed range", Field: "grams"}
+	}
+	return nil
+}
+
+// misenteredUnit asks whether an implausible grams value was meant as
+// kilograms or pounds, when either reading is a plausible cat weight.
+func misenteredUnit(grams int) error {
+	value := strconv.Itoa(grams)
+	var candidates []string
+	if plausibleGrams(grams * 1000) {
+		candidates = append(candidates, value+" "+UnitKilograms)
+	}
+	if plausibleGrams((grams*gramsPerPoundNumerator + gramsPerPoundDenominator/2) / gramsPerPoundDenominator) {
+		candidates = append(candidates, value+" "+UnitPounds)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return NeedsClarification{
+		Field:      "grams",
+		Question:   value + " g is not a plausible cat weight. Which unit was meant?",
+		Candidates: candidates,
+	}
+}
+
+// misenteredAmountUnit asks whether an amount given in grams, or without a
+// unit, was meant as kilograms or pounds. Gram amounts that are a plausible
+// cat weight are left alone, fractional or not.
+func misenteredAmountUnit(amount string, unit string, match []string) error {
+	if unit == UnitGrams {
+		if whole, _ := strconv.Atoi(match[1]); plausibleGrams(whole) {
+			return nil
+		}
+	}
+	var candidates []string
+	for _, candidate := range []string{UnitKilograms, UnitPounds} {
+		if grams, err := convertAmount(match, candidate); err == nil && plausibleGrams(grams) {
+			candidates = append(candidates, amount+" "+candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	question := amount + " g is not a plausible cat weight. Which unit was meant?"
+	if unit == "" {
+		question = amount + " has no unit. Which unit was meant?"
+	}
+	return NeedsClarification{
+		Field:      "unit",
+		Question:   question,
+		Candidates: candidates,
+	}
+}
+
+func plausibleGrams(grams int) bool {
+	return grams >= MinWeightGrams && grams <= MaxWeightGrams
+}
+
+func pow10(exponent int) int {
+	result := 1
+	for ; exponent > 0; exponent-- {
+		result *= 10
+	}
+	return result
+}
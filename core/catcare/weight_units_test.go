@@ -0,0 +1,154 @@
+package catcare
+
+import "testing"
+
+func TestLogWeightGivenAmountWithUnitWhenLogThenConvertsExactlyToGrams(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		amount string
+		unit   string
+		grams  int
+	}{
+		{name: "kilograms", amount: "4.2", unit: UnitKilograms, grams: 4200},
+		{name: "kilograms with trailing zeros", amount: "4.2500", unit: UnitKilograms, grams: 4250},
+		{name: "whole kilograms", amount: "4", unit: UnitKilograms, grams: 4000},
+		{name: "grams", amount: "4150", unit: UnitGrams, grams: 4150},
+		{name: "pounds rounded to the nearest gram", amount: "9.5", unit: UnitPounds, grams: 4309},
+		{name: "pounds with two decimals", amount: "9.25", unit: UnitPounds, grams: 4196},
+		{name: "whole pounds", amount: "10", unit: UnitPounds, grams: 4536},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := aggregate.Decide(LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Amount: tc.amount, Unit: tc.unit})
+			if err != nil {
+				t.Fatalf("decide log weight: %v", err)
+			}
+			logged := events[0].(WeightLogged)
+			if logged.Grams != tc.grams {
+				t.Fatalf("grams = %d, want %d", logged.Grams, tc.grams)
+			}
+			if logged.EnteredAmount != tc.amount || logged.EnteredUnit != tc.unit {
+				t.Fatalf("entered = %q %q, want %q %q", logged.EnteredAmount, logged.EnteredUnit, tc.amount, tc.unit)
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenInvalidWeightInputWhenLogThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		command LogWeight
+		code    string
+		field   string
+	}{
+		{name: "more precise than grams in kg", command: LogWeight{Amount: "4.2345", Unit: UnitKilograms}, code: CodeLossyWeight, field: "amount"},
+		{name: "fractional grams", command: LogWeight{Amount: "4200.5", Unit: UnitGrams}, code: CodeLossyWeight, field: "amount"},
+		{name: "pounds below gram resolution", command: LogWeight{Amount: "9.125", Unit: UnitPounds}, code: CodeLossyWeight, field: "amount"},
+		{name: "unknown unit", command: LogWeight{Amount: "9.5", Unit: "lbs"}, code: CodeInvalidWeightUnit, field: "unit"},
+		{name: "missing unit", command: LogWeight{Amount: "4200"}, code: CodeInvalidWeightUnit, field: "unit"},
+		{name: "decimal comma", command: LogWeight{Amount: "4,2", Unit: UnitKilograms}, code: CodeInvalidWeight, field: "amount"},
+		{name: "grams and amount", command: LogWeight{Grams: 4200, Amount: "4.2", Unit: UnitKilograms}, code: CodeInvalidWeight, field: "grams"},
+		{name: "zero", command: LogWeight{Amount: "0.0", Unit: UnitKilograms}, code: CodeInvalidWeight, field: "amount"},
+		{name: "absurd pounds", command: LogWeight{Amount: "80", Unit: UnitPounds}, code: CodeAbsurdWeight, field: "amount"},
+		{name: "body condition score above scale", command: LogWeight{Grams: 4200, BodyConditionScore: 10}, code: CodeInvalidBodyConditionScore, field: "body_condition_score"},
+		{name: "body condition score below scale", command: LogWeight{Grams: 4200, BodyConditionScore: -1}, code: CodeInvalidBodyConditionScore, field: "body_condition_score"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.command.CommandID = "cmd-2"
+			tc.command.At = "2026-02-10T08:00:00Z"
+			_, err := aggregate.Decide(tc.command)
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %v", err)
+			}
+			if rejection.Code != tc.code || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %q on %q", tc.code, tc.field, rejection.Code, rejection.Field)
+			}
+		})
+	}
+}
+
+func TestLogWeightGivenBodyConditionScoreWhenLogThenRecordsIt(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4200, BodyConditionScore: 6})
+	if err != nil {
+		t.Fatalf("decide log weight: %v", err)
+	}
+	if score := events[0].(WeightLogged).BodyConditionScore; score != 6 {
+		t.Fatalf("body condition score = %d, want 6", score)
+	}
+}
+
+func TestSetIdealWeightRangeGivenVetInputWhenSetThenDerivesStatus(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+	if status := aggregate.IdealWeightStatus(4200); status != "" {
+		t.Fatalf("status without range = %q, want empty", status)
+	}
+
+	events, err := aggregate.Decide(SetIdealWeightRange{CommandID: "cmd-2", MinGrams: 3800, MaxGrams: 4500, VetName: "Dr. Lima"})
+	if err != nil {
+		t.Fatalf("decide set ideal weight range: %v", err)
+	}
+	if err := aggregate.Apply(events[0]); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	for grams, want := range map[int]string{3700: "below", 3800: "within", 4500: "within", 4600: "above"} {
+		if status := aggregate.IdealWeightStatus(grams); status != want {
+			t.Fatalf("status(%d) = %q, want %q", grams, status, want)
+		}
+	}
+}
+
+func TestSetIdealWeightRangeGivenInvalidRangeWhenSetThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(registeredCatEvents())
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		command SetIdealWeightRange
+		code    string
+		field   string
+	}{
+		{name: "inverted range", command: SetIdealWeightRange{MinGrams: 4500, MaxGrams: 3800, VetName: "Dr. Lima"}, code: CodeInvalidIdealWeightRange, field: "max_grams"},
+		{name: "absurd minimum", command: SetIdealWeightRange{MinGrams: 4, MaxGrams: 4500, VetName: "Dr. Lima"}, code: CodeAbsurdWeight, field: "min_grams"},
+		{name: "missing vet", command: SetIdealWeightRange{MinGrams: 3800, MaxGrams: 4500}, code: CodeInvalidVetName, field: "vet_name"},
+		{name: "unknown visit", command: SetIdealWeightRange{MinGrams: 3800, MaxGrams: 4500, VetName: "Dr. Lima", VisitID: "visit-missing"}, code: CodeUnknownVetVisit, field: "visit_id"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.command.CommandID = "cmd-2"
+			_, err := aggregate.Decide(tc.command)
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %v", err)
+			}
+			if rejection.Code != tc.code || rejection.Field != tc.field {
+				t.Fatalf("expected %q on %q, got %q on %q", tc.code, tc.field, rejection.Code, rejection.Field)
+			}
+		})
+	}
+}
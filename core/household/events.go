@@ -0,0 +1,18 @@
+package household
+
+// EventName returns the stable name an event is persisted under.
+func EventName(event Event) string {
+	return event.eventName()
+}
+
+// EventTypes returns a zero value of every event the aggregate emits, so
+// adapters can register them with their codecs.
+func EventTypes() []Event {
+	return []Event{
+		HouseholdCreated{},
+		CatAddedToHousehold{},
+		CatRemovedFromHousehold{},
+		CaretakerInvited{},
+		CaretakerRemoved{},
+	}
+}
@@ -0,0 +1,426 @@
+package household
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/wastingnotime/zeroapps/core/internal/freetext"
+)
+
+const (
+	MaxNameRunes      = 80
+	MaxReferenceRunes = 200
+	MaxReasonRunes    = 1000
+)
+
+const (
+	CodeAlreadyCreated         = "already_created"
+	CodeNotCreated             = "not_created"
+	CodeDuplicateCommand       = "duplicate_command"
+	CodeInvalidCommand         = "invalid_command"
+	CodeInvalidCommandID       = "invalid_command_id"
+	CodeInvalidName            = "invalid_name"
+	CodeTextTooLong            = "text_too_long"
+	CodeInvalidCharacters      = "invalid_characters"
+	CodeInvalidCatID           = "invalid_cat_id"
+	CodeUnknownCat             = "unknown_cat"
+	CodeCatAlreadyMember       = "cat_already_member"
+	CodeCatInAnotherHousehold  = "cat_in_another_household"
+	CodeCatNotMember           = "cat_not_member"
+	CodeInvalidCaretaker       = "invalid_caretaker"
+	CodeCaretakerAlreadyMember = "caretaker_already_member"
+	CodeCaretakerNotMember     = "caretaker_not_member"
+	CodeOwnerNotRemovable      = "owner_not_removable"
+)
+
+type Rejection struct {
+	Code    string
+	Message string
+	Field   string
+}
+
+func (r Rejection) Error() string {
+	if r.Field == "" {
+		return r.Code + ": " + r.Message
+	}
+	return r.Code + ": " + r.Field + " " + r.Message
+}
+
+type Command interface {
+	commandName() string
+	commandID() string
+}
+
+type Event interface {
+	eventName() string
+	commandID() string
+}
+
+// CommandID returns the idempotency key of a command.
+func CommandID(command Command) string {
+	return command.commandID()
+}
+
+// CreateHousehold starts a household owned by OwnerRef, an opaque reference
+// to a person owned by the adapter. The owner is its first caretaker.
+type CreateHousehold struct {
+	CommandID string
+	Name      string
+	OwnerRef  string
+}
+
+func (c CreateHousehold) commandName() string { return "CreateHousehold" }
+func (c CreateHousehold) commandID() string   { return c.CommandID }
+
+// AddCatToHousehold adds the cat whose CatCare stream is CatID. A cat belongs
+// to at most one household at a time; the service enforces this across
+// households and tells the aggregate which cats exist (WithKnownCats).
+type AddCatToHousehold struct {
+	CommandID string
+	CatID     string
+}
+
+func (c AddCatToHousehold) commandName() string { return "AddCatToHousehold" }
+func (c AddCatToHousehold) commandID() string   { return c.CommandID }
+
+type RemoveCatFromHousehold struct {
+	CommandID string
+	CatID     string
+	Reason    string
+}
+
+func (c RemoveCatFromHousehold) commandName() string { return "RemoveCatFromHousehold" }
+func (c RemoveCatFromHousehold) commandID() string   { return c.CommandID }
+
+// InviteCaretaker grants CaretakerRef shared access to every cat of the
+// household.
+type InviteCaretaker struct {
+	CommandID    string
+	CaretakerRef string
+}
+
+func (c InviteCaretaker) commandName() string { return "InviteCaretaker" }
+func (c InviteCaretaker) commandID() string   { return c.CommandID }
+
+type RemoveCaretaker struct {
+	CommandID    string
+	CaretakerRef string
+	Reason       string
+}
+
+func (c RemoveCaretaker) commandName() string { return "RemoveCaretaker" }
+func (c RemoveCaretaker) commandID() string   { return c.CommandID }
+
+type HouseholdCreated struct {
+	CommandID   string
+	HouseholdID string
+	Name        string
+	OwnerRef    string
+}
+
+func (e HouseholdCreated) eventName() string { return "HouseholdCreated" }
+func (e HouseholdCreated) commandID() string { return e.CommandID }
+
+type CatAddedToHousehold struct {
+	CommandID   string
+	HouseholdID string
+	CatID       string
+}
+
+func (e CatAddedToHousehold) eventName() string { return "CatAddedToHousehold" }
+func (e CatAddedToHousehold) commandID() string { return e.CommandID }
+
+type CatRemovedFromHousehold struct {
+	CommandID   string
+	HouseholdID string
+	CatID       string
+	Reason      string
+}
+
+func (e CatRemovedFromHousehold) eventName() string { return "CatRemovedFromHousehold" }
+func (e CatRemovedFromHousehold) commandID() string { return e.CommandID }
+
+type CaretakerInvited struct {
+	CommandID    string
+	HouseholdID  string
+	CaretakerRef string
+}
+
+func (e CaretakerInvited) eventName() string { return "CaretakerInvited" }
+func (e CaretakerInvited) commandID() string { return e.CommandID }
+
+type CaretakerRemoved struct {
+	CommandID    string
+	HouseholdID  string
+	CaretakerRef string
+	Reason       string
+}
+
+func (e CaretakerRemoved) eventName() string { return "CaretakerRemoved" }
+func (e CaretakerRemoved) commandID() string { return e.CommandID }
+
+// Household groups cats under shared caretaker access. CatIDs and
+// CaretakerRefs keep membership order; the owner is always the first
+// caretaker.
+type Household struct {
+	HouseholdID         string
+	Name                string
+	OwnerRef            string
+	Created             bool
+	CatIDs              []string
+	CaretakerRefs       []string
+	processedCommandIDs map[string]struct{}
+	knownCats           map[string]struct{}
+}
+
+// Option configures decision inputs that are not derived from events.
+type Option func(*Household)
+
+// WithKnownCats lists the cats the adapter has confirmed exist and are
+// active. AddCatToHousehold only accepts these.
+func WithKnownCats(catIDs ...string) Option {
+	return func(h *Household) {
+		h.knownCats = map[string]struct{}{}
+		for _, catID := range catIDs {
+			h.knownCats[catID] = struct{}{}
+		}
+	}
+}
+
+func New(options ...Option) *Household {
+	household := &Household{
+		processedCommandIDs: map[string]struct{}{},
+		knownCats:           map[string]struct{}{},
+	}
+	for _, option := range options {
+		option(household)
+	}
+	return household
+}
+
+func LoadFrom(events []Event, options ...Option) (*Household, error) {
+	household := New(options...)
+	for _, event := range events {
+		if err := household.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return household, nil
+}
+
+func (h *Household) Decide(command Command) ([]Event, error) {
+	if command == nil {
+		return nil, Rejection{Code: CodeInvalidCommand, Message: "command is required"}
+	}
+	if command.commandID() == "" {
+		return nil, Rejection{Code: CodeInvalidCommandID, Message: "must not be empty", Field: "command_id"}
+	}
+	if _, exists := h.processedCommandIDs[command.commandID()]; exists {
+		return nil, Rejection{Code: CodeDuplicateCommand, Message: "already applied", Field: "command_id"}
+	}
+	if _, creating := command.(CreateHousehold); !creating && !h.Created {
+		return nil, Rejection{Code: CodeNotCreated, Message: "household must be created first"}
+	}
+
+	switch cmd := command.(type) {
+	case CreateHousehold:
+		return h.decideCreateHousehold(cmd)
+	case AddCatToHousehold:
+		return h.decideAddCatToHousehold(cmd)
+	case RemoveCatFromHousehold:
+		return h.decideRemoveCatFromHousehold(cmd)
+	case InviteCaretaker:
+		return h.decideInviteCaretaker(cmd)
+	case RemoveCaretaker:
+		return h.decideRemoveCaretaker(cmd)
+	default:
+		return nil, Rejection{Code: CodeInvalidCommand, Message: "unknown command"}
+	}
+}
+
+func (h *Household) Apply(event Event) error {
+	switch ev := event.(type) {
+	case HouseholdCreated:
+		h.HouseholdID = ev.HouseholdID
+		h.Name = ev.Name
+		h.OwnerRef = ev.OwnerRef
+		h.Created = true
+		h.CaretakerRefs = append(h.CaretakerRefs, ev.OwnerRef)
+	case CatAddedToHousehold:
+		h.CatIDs = append(h.CatIDs, ev.CatID)
+	case CatRemovedFromHousehold:
+		h.CatIDs = without(h.CatIDs, ev.CatID)
+	case CaretakerInvited:
+		h.CaretakerRefs = append(h.CaretakerRefs, ev.CaretakerRef)
+	case CaretakerRemoved:
+		h.CaretakerRefs = without(h.CaretakerRefs, ev.CaretakerRef)
+	default:
+		return Rejection{Code: CodeInvalidCommand, Message: "unknown event"}
+	}
+
+	if event.commandID() != "" {
+		h.processedCommandIDs[event.commandID()] = struct{}{}
+	}
+	return nil
+}
+
+// HasCat reports whether catID is currently a member.
+func (h *Household) HasCat(catID string) bool {
+	return contains(h.CatIDs, catID)
+}
+
+// HasCaretaker reports whether caretakerRef currently has access.
+func (h *Household) HasCaretaker(caretakerRef string) bool {
+	return contains(h.CaretakerRefs, caretakerRef)
+}
+
+func (h *Household) decideCreateHousehold(cmd CreateHousehold) ([]Event, error) {
+	if h.Created {
+		return nil, Rejection{Code: CodeAlreadyCreated, Message: "household already created"}
+	}
+	name, err := requiredText("name", cmd.Name, MaxNameRunes, CodeInvalidName)
+	if err != nil {
+		return nil, err
+	}
+	ownerRef, err := requiredText("owner_ref", cmd.OwnerRef, MaxReferenceRunes, CodeInvalidCaretaker)
+	if err != nil {
+		return nil, err
+	}
+
+	event := HouseholdCreated{
+		CommandID:   cmd.CommandID,
+		HouseholdID: "household-" + cmd.CommandID,
+		Name:        name,
+		OwnerRef:    ownerRef,
+	}
+	return []Event{event}, nil
+}
+
+func (h *Household) decideAddCatToHousehold(cmd AddCatToHousehold) ([]Event, error) {
+	catID := strings.TrimSpace(cmd.CatID)
+	if catID == "" {
+		return nil, Rejection{Code: CodeInvalidCatID, Message: "must not be empty", Field: "cat_id"}
+	}
+	if h.HasCat(catID) {
+		return nil, Rejection{Code: CodeCatAlreadyMember, Message: "cat is already in this household", Field: "cat_id"}
+	}
+	if _, known := h.knownCats[catID]; !known {
+		return nil, Rejection{Code: CodeUnknownCat, Message: "no active cat with this id", Field: "cat_id"}
+	}
+
+	event := CatAddedToHousehold{
+		CommandID:   cmd.CommandID,
+		HouseholdID: h.HouseholdID,
+		CatID:       catID,
+	}
+	return []Event{event}, nil
+}
+
+func (h *Household) decideRemoveCatFromHousehold(cmd RemoveCatFromHousehold) ([]Event, error) {
+	catID := strings.TrimSpace(cmd.CatID)
+	if catID == "" {
+		return nil, Rejection{Code: CodeInvalidCatID, Message: "must not be empty", Field: "cat_id"}
+	}
+	if !h.HasCat(catID) {
+		return nil, Rejection{Code: CodeCatNotMember, Message: "cat is not in this household", Field: "cat_id"}
+	}
+	reason, err := text("reason", cmd.Reason, MaxReasonRunes)
+	if err != nil {
+		return nil, err
+	}
+
+	event := CatRemovedFromHousehold{
+		CommandID:   cmd.CommandID,
+		HouseholdID: h.HouseholdID,
+		CatID:       catID,
+		Reason:      reason,
+	}
+	return []Event{event}, nil
+}
+
+func (h *Household) decideInviteCaretaker(cmd InviteCaretaker) ([]Event, error) {
+	caretakerRef, err := requiredText("caretaker_ref", cmd.CaretakerRef, MaxReferenceRunes, CodeInvalidCaretaker)
+	if err != nil {
+		return nil, err
+	}
+	if h.HasCaretaker(caretakerRef) {
+		return nil, Rejection{Code: CodeCaretakerAlreadyMember, Message: "caretaker already has access", Field: "caretaker_ref"}
+	}
+
+	event := CaretakerInvited{
+		CommandID:    cmd.CommandID,
+		HouseholdID:  h.HouseholdID,
+		CaretakerRef: caretakerRef,
+	}
+	return []Event{event}, nil
+}
+
+func (h *Household) decideRemoveCaretaker(cmd RemoveCaretaker) ([]Event, error) {
+	caretakerRef, err := requiredText("caretaker_ref", cmd.CaretakerRef, MaxReferenceRunes, CodeInvalidCaretaker)
+	if err != nil {
+		return nil, err
+	}
+	if caretakerRef == h.OwnerRef {
+		return nil, Rejection{Code: CodeOwnerNotRemovable, Message: "the owner cannot be removed", Field: "caretaker_ref"}
+	}
+	if !h.HasCaretaker(caretakerRef) {
+		return nil, Rejection{Code: CodeCaretakerNotMember, Message: "caretaker has no access", Field: "caretaker_ref"}
+	}
+	reason, err := text("reason", cmd.Reason, MaxReasonRunes)
+	if err != nil {
+		return nil, err
+	}
+
+	event := CaretakerRemoved{
+		CommandID:    cmd.CommandID,
+		HouseholdID:  h.HouseholdID,
+		CaretakerRef: caretakerRef,
+		Reason:       reason,
+	}
+	return []Event{event}, nil
+}
+
+// text normalizes free text like CatCare's text policy: NFC, trimmed, no
+// control or invisible characters; only reason may span lines.
+func text(field string, value string, maxRunes int) (string, error) {
+	normalized, problem := freetext.Clean(value, maxRunes, field == "reason")
+	switch problem {
+	case freetext.InvalidUTF8:
+		return "", Rejection{Code: CodeInvalidCharacters, Message: "must be valid UTF-8", Field: field}
+	case freetext.InvalidCharacters:
+		return "", Rejection{Code: CodeInvalidCharacters, Message: "must not contain control or invisible characters", Field: field}
+	case freetext.TooLong:
+		return "", Rejection{Code: CodeTextTooLong, Message: "must be at most " + strconv.Itoa(maxRunes) + " characters", Field: field}
+	}
+	return normalized, nil
+}
+
+func requiredText(field string, value string, maxRunes int, emptyCode string) (string, error) {
+	normalized, err := text(field, value, maxRunes)
+	if err != nil {
+		return "", err
+	}
+	if normalized == "" {
+		return "", Rejection{Code: emptyCode, Message: "must not be empty", Field: field}
+	}
+	return normalized, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+func without(values []string, value string) []string {
+	kept := make([]string, 0, len(values))
+	for _, existing := range values {
+		if existing != value {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
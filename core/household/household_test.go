@@ -0,0 +1,121 @@
+package household
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createdHouseholdEvents() []Event {
+	return []Event{
+		HouseholdCreated{CommandID: "cmd-1", HouseholdID: "household-cmd-1", Name: "Casa Silva", OwnerRef: "person-ana"},
+		CatAddedToHousehold{CommandID: "cmd-2", HouseholdID: "household-cmd-1", CatID: "cat-miso"},
+		CaretakerInvited{CommandID: "cmd-3", HouseholdID: "household-cmd-1", CaretakerRef: "person-bia"},
+	}
+}
+
+func TestCreateHouseholdGivenEmptyStreamWhenCreateThenOwnerIsFirstCaretaker(t *testing.T) {
+	aggregate, err := LoadFrom(nil)
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	events, err := aggregate.Decide(CreateHousehold{CommandID: "cmd-1", Name: " Casa Silva ", OwnerRef: "person-ana"})
+	if err != nil {
+		t.Fatalf("decide create household: %v", err)
+	}
+	created, ok := events[0].(HouseholdCreated)
+	if !ok {
+		t.Fatalf("expected HouseholdCreated, got %T", events[0])
+	}
+	if created.HouseholdID != "household-cmd-1" || created.Name != "Casa Silva" {
+		t.Fatalf("unexpected event %+v", created)
+	}
+	if err := aggregate.Apply(created); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !reflect.DeepEqual(aggregate.CaretakerRefs, []string{"person-ana"}) {
+		t.Fatalf("caretakers = %v, want the owner", aggregate.CaretakerRefs)
+	}
+}
+
+func TestDecideGivenCreatedHouseholdWhenMembershipChangesThenEmitsEvents(t *testing.T) {
+	aggregate, err := LoadFrom(createdHouseholdEvents(), WithKnownCats("cat-taro"))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	commands := []Command{
+		AddCatToHousehold{CommandID: "cmd-4", CatID: "cat-taro"},
+		RemoveCatFromHousehold{CommandID: "cmd-5", CatID: "cat-miso", Reason: "Rehomed"},
+		RemoveCaretaker{CommandID: "cmd-6", CaretakerRef: "person-bia"},
+		InviteCaretaker{CommandID: "cmd-7", CaretakerRef: "person-caio"},
+	}
+	for _, command := range commands {
+		events, err := aggregate.Decide(command)
+		if err != nil {
+			t.Fatalf("decide %T: %v", command, err)
+		}
+		for _, event := range events {
+			if err := aggregate.Apply(event); err != nil {
+				t.Fatalf("apply %T: %v", event, err)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(aggregate.CatIDs, []string{"cat-taro"}) {
+		t.Fatalf("cats = %v", aggregate.CatIDs)
+	}
+	if !reflect.DeepEqual(aggregate.CaretakerRefs, []string{"person-ana", "person-caio"}) {
+		t.Fatalf("caretakers = %v", aggregate.CaretakerRefs)
+	}
+}
+
+func TestDecideGivenCreatedHouseholdWhenMembershipInvariantBrokenThenRejects(t *testing.T) {
+	aggregate, err := LoadFrom(createdHouseholdEvents(), WithKnownCats("cat-miso", "cat-taro"))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		command Command
+		code    string
+	}{
+		{name: "create twice", command: CreateHousehold{CommandID: "cmd-9", Name: "Again", OwnerRef: "person-ana"}, code: CodeAlreadyCreated},
+		{name: "cat already member", command: AddCatToHousehold{CommandID: "cmd-9", CatID: "cat-miso"}, code: CodeCatAlreadyMember},
+		{name: "unknown cat", command: AddCatToHousehold{CommandID: "cmd-9", CatID: "cat-ghost"}, code: CodeUnknownCat},
+		{name: "blank cat id", command: AddCatToHousehold{CommandID: "cmd-9", CatID: " "}, code: CodeInvalidCatID},
+		{name: "remove cat not member", command: RemoveCatFromHousehold{CommandID: "cmd-9", CatID: "cat-taro"}, code: CodeCatNotMember},
+		{name: "invite existing caretaker", command: InviteCaretaker{CommandID: "cmd-9", CaretakerRef: "person-bia"}, code: CodeCaretakerAlreadyMember},
+		{name: "invite with control character", command: InviteCaretaker{CommandID: "cmd-9", CaretakerRef: "person\x00"}, code: CodeInvalidCharacters},
+		{name: "remove owner", command: RemoveCaretaker{CommandID: "cmd-9", CaretakerRef: "person-ana"}, code: CodeOwnerNotRemovable},
+		{name: "remove stranger", command: RemoveCaretaker{CommandID: "cmd-9", CaretakerRef: "person-zed"}, code: CodeCaretakerNotMember},
+		{name: "replayed command", command: InviteCaretaker{CommandID: "cmd-3", CaretakerRef: "person-caio"}, code: CodeDuplicateCommand},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := aggregate.Decide(tc.command)
+			rejection, ok := err.(Rejection)
+			if !ok {
+				t.Fatalf("expected Rejection, got %v", err)
+			}
+			if rejection.Code != tc.code {
+				t.Fatalf("expected %q, got %q", tc.code, rejection.Code)
+			}
+		})
+	}
+}
+
+func TestDecideGivenEmptyStreamWhenNotCreateThenRejectsNotCreated(t *testing.T) {
+	aggregate, err := LoadFrom(nil, WithKnownCats("cat-miso"))
+	if err != nil {
+		t.Fatalf("load aggregate: %v", err)
+	}
+
+	_, err = aggregate.Decide(AddCatToHousehold{CommandID: "cmd-1", CatID: "cat-miso"})
+	rejection, ok := err.(Rejection)
+	if !ok || rejection.Code != CodeNotCreated {
+		t.Fatalf("expected %q, got %v", CodeNotCreated, err)
+	}
+}
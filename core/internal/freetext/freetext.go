@@ -0,0 +1,75 @@
+// Package freetext normalizes and checks free-text input for the core
+// aggregates. Each aggregate maps a Problem to its own rejection.
+package freetext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type Problem int
+
+const (
+	OK Problem = iota
+	InvalidUTF8
+	InvalidCharacters
+	TooLong
+)
+
+// invisibleRunes are graphic per the unicode package yet render as blank and
+// are used to smuggle look-alike input.
+var invisibleRunes = map[rune]bool{
+	0x115F: true, // HANGUL CHOSEONG FILLER
+	0x1160: true, // HANGUL JUNGSEONG FILLER
+	0x2800: true, // BRAILLE PATTERN BLANK
+	0x3164: true, // HANGUL FILLER
+	0xFFA0: true, // HALFWIDTH HANGUL FILLER
+	0xFFFD: true, // REPLACEMENT CHARACTER, a sign of mangled input
+}
+
+// zeroWidthJoiner is invisible on its own but glues emoji sequences such as
+// family and profession emoji together, so it is allowed between two visible,
+// non-space runes. Emoji variation selectors (U+FE0F) are marks and pass as
+// graphic.
+const zeroWidthJoiner = '\u200d'
+
+// Clean normalizes value to NFC and trims it, then checks it: only graphic,
+// visible runes (plus line breaks and tabs when multiline) and at most
+// maxRunes runes; maxRunes <= 0 means no limit. CRLF becomes LF in multiline
+// text.
+func Clean(value string, maxRunes int, multiline bool) (string, Problem) {
+	if !utf8.ValidString(value) {
+		return "", InvalidUTF8
+	}
+	normalized := strings.TrimSpace(norm.NFC.String(value))
+	if multiline {
+		normalized = strings.ReplaceAll(normalized, "\r\n", "\n")
+	}
+	runes := []rune(normalized)
+	for index, r := range runes {
+		if r == zeroWidthJoiner && index > 0 && index < len(runes)-1 && joinable(runes[index-1]) && joinable(runes[index+1]) {
+			continue
+		}
+		if !allowedRune(r, multiline) {
+			return "", InvalidCharacters
+		}
+	}
+	if maxRunes > 0 && utf8.RuneCountInString(normalized) > maxRunes {
+		return "", TooLong
+	}
+	return normalized, OK
+}
+
+func allowedRune(r rune, multiline bool) bool {
+	if r == '\n' || r == '\t' {
+		return multiline
+	}
+	return unicode.IsGraphic(r) && !invisibleRunes[r]
+}
+
+func joinable(r rune) bool {
+	return r != zeroWidthJoiner && !unicode.IsSpace(r) && unicode.IsGraphic(r) && !invisibleRunes[r]
+}
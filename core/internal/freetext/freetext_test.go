@@ -0,0 +1,59 @@
+package freetext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanGivenInputWhenCheckedThenNormalizesOrReportsProblem(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		maxRunes  int
+		multiline bool
+		want      string
+		problem   Problem
+	}{
+		{name: "trimmed", input: "  Miso  ", want: "Miso"},
+		{name: "combining acute composes", input: "Jose\u0301", want: "Jos\u00e9"},
+		{name: "marks reordered before composing", input: "a\u0323\u0302", want: "\u1ead"},
+		{name: "singleton angstrom", input: "\u212b", want: "\u00c5"},
+		{name: "hangul jamo compose", input: "\u1100\u1161\u11a8", want: "\uac01"},
+		{name: "invalid utf-8", input: "Mi\xffso", problem: InvalidUTF8},
+		{name: "nul", input: "Mi\x00so", problem: InvalidCharacters},
+		{name: "escape", input: "Mi\x1b[31mso", problem: InvalidCharacters},
+		{name: "delete", input: "Mi\x7fso", problem: InvalidCharacters},
+		{name: "zero width space", input: "Mi\u200bso", problem: InvalidCharacters},
+		{name: "family emoji joined", input: "\U0001f468\u200d\U0001f469\u200d\U0001f467", want: "\U0001f468\u200d\U0001f469\u200d\U0001f467"},
+		{name: "profession emoji with variation selector", input: "\U0001f469\u200d\u2695\ufe0f", want: "\U0001f469\u200d\u2695\ufe0f"},
+		{name: "zero width joiner at the end", input: "Miso\u200d", problem: InvalidCharacters},
+		{name: "zero width joiner doubled", input: "Mi\u200d\u200dso", problem: InvalidCharacters},
+		{name: "zero width joiner next to space", input: "Mi \u200dso", problem: InvalidCharacters},
+		{name: "right-to-left override", input: "Mi\u202eso", problem: InvalidCharacters},
+		{name: "byte order mark", input: "\ufeffMiso", problem: InvalidCharacters},
+		{name: "hangul filler", input: "Mi\u3164so", problem: InvalidCharacters},
+		{name: "replacement character", input: "Mi\ufffdso", problem: InvalidCharacters},
+		{name: "newline in single line", input: "Mi\nso", problem: InvalidCharacters},
+		{name: "tab in single line", input: "Mi\tso", problem: InvalidCharacters},
+		{name: "newline in multiline", input: "Ate well.\nNo vomiting.", multiline: true, want: "Ate well.\nNo vomiting."},
+		{name: "crlf becomes lf", input: "Ate well.\r\nNo vomiting.", multiline: true, want: "Ate well.\nNo vomiting."},
+		{name: "lone carriage return", input: "Ate well.\rNo vomiting.", multiline: true, problem: InvalidCharacters},
+		{name: "at the limit", input: strings.Repeat("\u00e9", 5), maxRunes: 5, want: strings.Repeat("\u00e9", 5)},
+		{name: "over the limit", input: strings.Repeat("a", 6), maxRunes: 5, problem: TooLong},
+		{name: "limit counts normalized runes", input: strings.Repeat("e\u0301", 5), maxRunes: 5, want: strings.Repeat("\u00e9", 5)},
+		{name: "limit ignores surrounding space", input: "  abcde  ", maxRunes: 5, want: "abcde"},
+		{name: "no limit", input: strings.Repeat("a", 10000), want: strings.Repeat("a", 10000)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, problem := Clean(tc.input, tc.maxRunes, tc.multiline)
+			if problem != tc.problem {
+				t.Fatalf("Clean(%q) problem = %v, want %v", tc.input, problem, tc.problem)
+			}
+			if got != tc.want {
+				t.Fatalf("Clean(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,11 @@ module github.com/wastingnotime/zeroapps
 
 go 1.25.1
 
+require (
+	golang.org/x/text v0.40.0
+	modernc.org/sqlite v1.46.1
+)
+
 require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
@@ -13,5 +18,4 @@ require (
 	modernc.org/libc v1.67.6 // indirect
 	modernc.org/mathutil v1.7.1 // indirect
 	modernc.org/memory v1.11.0 // indirect
-	modernc.org/sqlite v1.46.1 // indirect
 )
@@ -0,0 +1,116 @@
+package catcare
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+// DayIntake totals the meals of one calendar day against the diet plan in
+// effect on that day. PlannedGrams is zero when no plan applied yet.
+type DayIntake struct {
+	Date          string
+	Meals         int
+	GramsOffered  int
+	GramsEaten    int
+	PlannedGrams  int
+	PlannedMeals  int
+	PercentOfPlan int
+}
+
+// DailyIntake reports what a cat ate per day. A meal belongs to the calendar
+// day of its own timestamp offset, i.e. the caretaker's local day.
+type DailyIntake struct {
+	mu                sync.RWMutex
+	plansByStream     map[string][]core.DietPlanSet
+	daysByStream      map[string]map[string]*DayIntake
+	lastStreamVersion map[string]int
+}
+
+func NewDailyIntake() *DailyIntake {
+	return &DailyIntake{
+		plansByStream:     map[string][]core.DietPlanSet{},
+		daysByStream:      map[string]map[string]*DayIntake{},
+		lastStreamVersion: map[string]int{},
+	}
+}
+
+func (p *DailyIntake) Apply(_ context.Context, streamID string, version int, event core.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lastVersion := p.lastStreamVersion[streamID]
+	if version <= lastVersion {
+		return nil
+	}
+
+	switch ev := event.(type) {
+	case core.DietPlanSet:
+		p.plansByStream[streamID] = append(p.plansByStream[streamID], ev)
+	case core.MealLogged:
+		at, err := time.Parse(time.RFC3339, ev.At)
+		if err != nil {
+			return err
+		}
+		days, exists := p.daysByStream[streamID]
+		if !exists {
+			days = map[string]*DayIntake{}
+			p.daysByStream[streamID] = days
+		}
+		date := at.Format(time.DateOnly)
+		day, exists := days[date]
+		if !exists {
+			day = &DayIntake{Date: date}
+			days[date] = day
+		}
+		day.Meals++
+		day.GramsOffered += ev.GramsOffered
+		day.GramsEaten += ev.GramsEaten
+	}
+
+	p.lastStreamVersion[streamID] = version
+	return nil
+}
+
+// ListDailyIntake returns one entry per day with logged meals, sorted by date.
+func (p *DailyIntake) ListDailyIntake(streamID string) []DayIntake {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	days := make([]DayIntake, 0, len(p.daysByStream[streamID]))
+	for _, day := range p.daysByStream[streamID] {
+		intake := *day
+		if plan, ok := p.planOn(streamID, day.Date); ok {
+			intake.PlannedGrams = plan.DailyGrams
+			intake.PlannedMeals = plan.MealCount
+			intake.PercentOfPlan = intake.GramsEaten * 100 / plan.DailyGrams
+		}
+		days = append(days, intake)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// planOn returns the latest plan set with StartsOn on or before date. Plans
+// set later in the stream win over earlier ones with the same start.
+func (p *DailyIntake) planOn(streamID string, date string) (core.DietPlanSet, bool) {
+	var (
+		found core.DietPlanSet
+		ok    bool
+	)
+	for _, plan := range p.plansByStream[streamID] {
+		if plan.StartsOn > date {
+			continue
+		}
+		if !ok || plan.StartsOn >= found.StartsOn {
+			found = plan
+			ok = true
+		}
+	}
+	return found, ok
+}
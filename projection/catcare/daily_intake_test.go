@@ -0,0 +1,41 @@
+package catcare
+
+import (
+	"context"
+	"testing"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func TestListDailyIntakeGivenMealsAndPlanChangeWhenListThenComparesEachDayToPlanInEffect(t *testing.T) {
+	projection := NewDailyIntake()
+
+	events := []core.Event{
+		core.MealLogged{CommandID: "cmd-2", MealID: "meal-cmd-2", At: "2026-02-09T08:00:00Z", GramsOffered: 40, GramsEaten: 40},
+		core.DietPlanSet{CommandID: "cmd-3", PlanID: "diet-cmd-3", FoodBrand: "Acme Renal", DailyGrams: 60, MealCount: 2, StartsOn: "2026-02-10"},
+		core.MealLogged{CommandID: "cmd-4", MealID: "meal-cmd-4", At: "2026-02-10T08:00:00Z", GramsOffered: 30, GramsEaten: 30},
+		core.MealLogged{CommandID: "cmd-5", MealID: "meal-cmd-5", At: "2026-02-10T19:00:00Z", GramsOffered: 30, GramsEaten: 15},
+		core.DietPlanSet{CommandID: "cmd-6", PlanID: "diet-cmd-6", FoodBrand: "Acme Renal", DailyGrams: 80, MealCount: 4, StartsOn: "2026-02-11"},
+		core.MealLogged{CommandID: "cmd-7", MealID: "meal-cmd-7", At: "2026-02-11T23:30:00-03:00", GramsOffered: 20, GramsEaten: 20},
+	}
+	for index, event := range events {
+		if err := projection.Apply(context.Background(), "cat-1", index+2, event); err != nil {
+			t.Fatalf("apply event: %v", err)
+		}
+	}
+
+	days := projection.ListDailyIntake("cat-1")
+	expected := []DayIntake{
+		{Date: "2026-02-09", Meals: 1, GramsOffered: 40, GramsEaten: 40},
+		{Date: "2026-02-10", Meals: 2, GramsOffered: 60, GramsEaten: 45, PlannedGrams: 60, PlannedMeals: 2, PercentOfPlan: 75},
+		{Date: "2026-02-11", Meals: 1, GramsOffered: 20, GramsEaten: 20, PlannedGrams: 80, PlannedMeals: 4, PercentOfPlan: 25},
+	}
+	if len(days) != len(expected) {
+		t.Fatalf("expected %d days, got %+v", len(expected), days)
+	}
+	for index := range expected {
+		if days[index] != expected[index] {
+			t.Fatalf("day %d = %+v, want %+v", index, days[index], expected[index])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package catcare
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+type MissedDose struct {
+	PrescriptionID string
+	DrugName       string
+	DueAt          string
+}
+
+type medicationSchedule struct {
+	prescription core.MedicationPrescribed
+	dosesGivenAt []time.Time
+}
+
+// MedicationDoses tracks prescriptions and the doses given against them. A
+// scheduled dose counts as given when a dose was recorded within half the
+// dosing interval of its due time.
+type MedicationDoses struct {
+	mu                sync.RWMutex
+	schedulesByStream map[string]map[string]*medicationSchedule
+	lastStreamVersion map[string]int
+}
+
+func NewMedicationDoses() *MedicationDoses {
+	return &MedicationDoses{
+		schedulesByStream: map[string]map[string]*medicationSchedule{},
+		lastStreamVersion: map[string]int{},
+	}
+}
+
+func (p *MedicationDoses) Apply(_ context.Context, streamID string, version int, event core.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lastVersion := p.lastStreamVersion[streamID]
+	if version <= lastVersion {
+		return nil
+	}
+
+	switch ev := event.(type) {
+	case core.MedicationPrescribed:
+		schedules, exists := p.schedulesByStream[streamID]
+		if !exists {
+			schedules = map[string]*medicationSchedule{}
+			p.schedulesByStream[streamID] = schedules
+		}
+		schedules[ev.PrescriptionID] = &medicationSchedule{prescription: ev}
+	case core.DoseGiven:
+		schedule, exists := p.schedulesByStream[streamID][ev.PrescriptionID]
+		if !exists {
+			break
+		}
+		givenAt, err := time.Parse(time.RFC3339, ev.GivenAt)
+		if err != nil {
+			return err
+		}
+		schedule.dosesGivenAt = append(schedule.dosesGivenAt, givenAt)
+	}
+
+	p.lastStreamVersion[streamID] = version
+	return nil
+}
+
+// ListMissedDoses returns the doses due in [from, to] with no matching
+// administration, sorted by due time then prescription id.
+func (p *MedicationDoses) ListMissedDoses(streamID string, from time.Time, to time.Time) []MissedDose {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	type missed struct {
+		dueAt time.Time
+		dose  MissedDose
+	}
+	var found []missed
+	for _, schedule := range p.schedulesByStream[streamID] {
+		prescription := schedule.prescription
+		startsAt, err := time.Parse(time.RFC3339, prescription.StartsAt)
+		if err != nil {
+			continue
+		}
+		endsAt := to
+		if prescription.EndsAt != "" {
+			if parsed, err := time.Parse(time.RFC3339, prescription.EndsAt); err == nil && parsed.Before(endsAt) {
+				endsAt = parsed
+			}
+		}
+		interval := time.Duration(prescription.IntervalHours) * time.Hour
+		if interval <= 0 {
+			continue
+		}
+
+		for dueAt := startsAt; !dueAt.After(endsAt); dueAt = dueAt.Add(interval) {
+			if dueAt.Before(from) || schedule.givenNear(dueAt, interval/2) {
+				continue
+			}
+			found = append(found, missed{
+				dueAt: dueAt,
+				dose: MissedDose{
+					PrescriptionID: prescription.PrescriptionID,
+					DrugName:       prescription.DrugName,
+					DueAt:          dueAt.Format(time.RFC3339),
+				},
+			})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if !found[i].dueAt.Equal(found[j].dueAt) {
+			return found[i].dueAt.Before(found[j].dueAt)
+		}
+		return found[i].dose.PrescriptionID < found[j].dose.PrescriptionID
+	})
+	doses := make([]MissedDose, 0, len(found))
+	for _, item := range found {
+		doses = append(doses, item.dose)
+	}
+	return doses
+}
+
+func (s *medicationSchedule) givenNear(dueAt time.Time, tolerance time.Duration) bool {
+	for _, givenAt := range s.dosesGivenAt {
+		if !givenAt.Before(dueAt.Add(-tolerance)) && givenAt.Before(dueAt.Add(tolerance)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package catcare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func TestListMissedDosesGivenTwiceDailyPrescriptionWhenDoseSkippedThenListsIt(t *testing.T) {
+	projection := NewMedicationDoses()
+
+	events := []core.Event{
+		core.MedicationPrescribed{
+			CommandID:      "cmd-2",
+			PrescriptionID: "prescription-cmd-2",
+			DrugName:       "Amoxicillin",
+			Dose:           50,
+			Unit:           "mg",
+			Route:          core.RouteOral,
+			IntervalHours:  12,
+			StartsAt:       "2026-02-01T08:00:00Z",
+			EndsAt:         "2026-02-03T08:00:00Z",
+		},
+		core.DoseGiven{CommandID: "cmd-3", DoseID: "dose-cmd-3", PrescriptionID: "prescription-cmd-2", GivenAt: "2026-02-01T08:10:00Z"},
+		core.DoseGiven{CommandID: "cmd-4", DoseID: "dose-cmd-4", PrescriptionID: "prescription-cmd-2", GivenAt: "2026-02-01T20:30:00Z"},
+		core.DoseGiven{CommandID: "cmd-5", DoseID: "dose-cmd-5", PrescriptionID: "prescription-cmd-2", GivenAt: "2026-02-02T19:45:00Z"},
+	}
+	for index, event := range events {
+		if err := projection.Apply(context.Background(), "cat-1", index+2, event); err != nil {
+			t.Fatalf("apply event: %v", err)
+		}
+	}
+
+	missed := projection.ListMissedDoses("cat-1",
+		time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.February, 4, 0, 0, 0, 0, time.UTC),
+	)
+	expected := []string{"2026-02-02T08:00:00Z", "2026-02-03T08:00:00Z"}
+	if len(missed) != len(expected) {
+		t.Fatalf("expected %d missed doses, got %+v", len(expected), missed)
+	}
+	for index, dueAt := range expected {
+		if missed[index].DueAt != dueAt || missed[index].PrescriptionID != "prescription-cmd-2" {
+			t.Fatalf("missed[%d] = %+v, want due %s", index, missed[index], dueAt)
+		}
+	}
+
+	window := projection.ListMissedDoses("cat-1",
+		time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.February, 2, 12, 0, 0, 0, time.UTC),
+	)
+	if len(window) != 1 || window[0].DueAt != "2026-02-02T08:00:00Z" {
+		t.Fatalf("unexpected missed doses in window %+v", window)
+	}
+}
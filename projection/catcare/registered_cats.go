@@ -12,6 +12,7 @@ type RegisteredCat struct {
 	CatID     string
 	Name      string
 	BirthDate string
+	Status    string
 }
 
 type RegisteredCats struct {
@@ -42,6 +43,22 @@ func (p *RegisteredCats) Apply(_ context.Context, streamID string, version int,
 			CatID:     ev.CatID,
 			Name:      ev.Name,
 			BirthDate: ev.BirthDate,
+			Status:    core.LifecycleActive,
+		}
+	case core.CatRenamed:
+		if cat, exists := p.catsByID[ev.CatID]; exists {
+			cat.Name = ev.NewName
+			p.catsByID[ev.CatID] = cat
+		}
+	case core.CatMarkedDeceased:
+		if cat, exists := p.catsByID[ev.CatID]; exists {
+			cat.Status = core.LifecycleDeceased
+			p.catsByID[ev.CatID] = cat
+		}
+	case core.CatArchived:
+		if cat, exists := p.catsByID[ev.CatID]; exists {
+			cat.Status = core.LifecycleArchived
+			p.catsByID[ev.CatID] = cat
 		}
 	}
 
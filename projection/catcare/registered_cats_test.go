@@ -104,3 +104,57 @@ func TestListRegisteredCatsGivenReappliedVersionWhenApplyThenDoesNotDuplicate(t
 		t.Fatalf("expected exactly 1 cat, got %d", len(cats))
 	}
 }
+
+func TestListRegisteredCatsGivenRenamedCatWhenListThenReturnsNewName(t *testing.T) {
+	projection := NewRegisteredCats()
+
+	if err := projection.Apply(context.Background(), "cat-1", 1, core.CatRegistered{
+		CommandID: "cmd-1",
+		CatID:     "cat-1",
+		Name:      "Msio",
+		BirthDate: "2023-01-01",
+	}); err != nil {
+		t.Fatalf("apply registered: %v", err)
+	}
+	if err := projection.Apply(context.Background(), "cat-1", 2, core.CatRenamed{
+		CommandID: "cmd-2",
+		CatID:     "cat-1",
+		NewName:   "Miso",
+	}); err != nil {
+		t.Fatalf("apply renamed: %v", err)
+	}
+
+	cats := projection.ListRegisteredCats()
+	if len(cats) != 1 {
+		t.Fatalf("expected 1 cat, got %d", len(cats))
+	}
+	if cats[0].Name != "Miso" || cats[0].BirthDate != "2023-01-01" {
+		t.Fatalf("unexpected cat %+v", cats[0])
+	}
+}
+
+func TestListRegisteredCatsGivenArchivedCatWhenListThenKeepsCatWithArchivedStatus(t *testing.T) {
+	projection := NewRegisteredCats()
+
+	if err := projection.Apply(context.Background(), "cat-1", 1, core.CatRegistered{
+		CommandID: "cmd-1",
+		CatID:     "cat-1",
+		Name:      "Miso",
+	}); err != nil {
+		t.Fatalf("apply registered: %v", err)
+	}
+	if err := projection.Apply(context.Background(), "cat-1", 2, core.CatArchived{
+		CommandID: "cmd-2",
+		CatID:     "cat-1",
+	}); err != nil {
+		t.Fatalf("apply archived: %v", err)
+	}
+
+	cats := projection.ListRegisteredCats()
+	if len(cats) != 1 {
+		t.Fatalf("expected 1 cat, got %d", len(cats))
+	}
+	if cats[0].Status != core.LifecycleArchived {
+		t.Fatalf("expected status %q, got %q", core.LifecycleArchived, cats[0].Status)
+	}
+}
@@ -0,0 +1,91 @@
+package catcare
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+type VaccineStatus struct {
+	VaccineType    string
+	VaccinationID  string
+	LotNumber      string
+	AdministeredAt string
+	ValidUntil     string
+	Current        bool
+}
+
+// VaccinationStatus answers which vaccines were current or expired for a cat
+// on a given date, using the latest administration of each vaccine type.
+type VaccinationStatus struct {
+	mu                sync.RWMutex
+	recordsByStream   map[string][]core.VaccinationRecorded
+	lastStreamVersion map[string]int
+}
+
+func NewVaccinationStatus() *VaccinationStatus {
+	return &VaccinationStatus{
+		recordsByStream:   map[string][]core.VaccinationRecorded{},
+		lastStreamVersion: map[string]int{},
+	}
+}
+
+func (p *VaccinationStatus) Apply(_ context.Context, streamID string, version int, event core.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lastVersion := p.lastStreamVersion[streamID]
+	if version <= lastVersion {
+		return nil
+	}
+
+	switch ev := event.(type) {
+	case core.VaccinationRecorded:
+		p.recordsByStream[streamID] = append(p.recordsByStream[streamID], ev)
+	}
+
+	p.lastStreamVersion[streamID] = version
+	return nil
+}
+
+// VaccinesAsOf lists one status per vaccine type, sorted by type. Vaccines
+// administered after asOf are ignored; a vaccine is current through the whole
+// ValidUntil day in asOf's location.
+func (p *VaccinationStatus) VaccinesAsOf(streamID string, asOf time.Time) []VaccineStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	latest := map[string]core.VaccinationRecorded{}
+	latestAt := map[string]time.Time{}
+	for _, record := range p.recordsByStream[streamID] {
+		administeredAt, err := time.Parse(time.RFC3339, record.AdministeredAt)
+		if err != nil || administeredAt.After(asOf) {
+			continue
+		}
+		if previousAt, seen := latestAt[record.VaccineType]; seen && administeredAt.Before(previousAt) {
+			continue
+		}
+		latest[record.VaccineType] = record
+		latestAt[record.VaccineType] = administeredAt
+	}
+
+	asOfDate := asOf.Format(time.DateOnly)
+	statuses := make([]VaccineStatus, 0, len(latest))
+	for vaccineType, record := range latest {
+		statuses = append(statuses, VaccineStatus{
+			VaccineType:    vaccineType,
+			VaccinationID:  record.VaccinationID,
+			LotNumber:      record.LotNumber,
+			AdministeredAt: record.AdministeredAt,
+			ValidUntil:     record.ValidUntil,
+			Current:        asOfDate <= record.ValidUntil,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].VaccineType < statuses[j].VaccineType
+	})
+	return statuses
+}
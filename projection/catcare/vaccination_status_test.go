@@ -0,0 +1,91 @@
+package catcare
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func TestVaccinesAsOfGivenRecordedVaccinationsWhenQueriedThenReportsCurrentAndExpired(t *testing.T) {
+	projection := NewVaccinationStatus()
+
+	events := []core.Event{
+		core.VaccinationRecorded{
+			CommandID:      "cmd-2",
+			VaccinationID:  "vaccination-cmd-2",
+			VaccineType:    "rabies",
+			LotNumber:      "RB-2024-01",
+			AdministeredAt: "2024-03-01T10:00:00Z",
+			ValidUntil:     "2025-03-01",
+		},
+		core.VaccinationRecorded{
+			CommandID:      "cmd-3",
+			VaccinationID:  "vaccination-cmd-3",
+			VaccineType:    "FVRCP",
+			LotNumber:      "FV-9",
+			AdministeredAt: "2025-01-10T10:00:00Z",
+			ValidUntil:     "2028-01-10",
+		},
+		core.VaccinationRecorded{
+			CommandID:      "cmd-4",
+			VaccinationID:  "vaccination-cmd-4",
+			VaccineType:    "rabies",
+			LotNumber:      "RB-2026-07",
+			AdministeredAt: "2026-02-01T10:00:00Z",
+			ValidUntil:     "2027-02-01",
+		},
+	}
+	for index, event := range events {
+		if err := projection.Apply(context.Background(), "cat-1", index+2, event); err != nil {
+			t.Fatalf("apply event: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		asOf     time.Time
+		expected []VaccineStatus
+	}{
+		{
+			name: "rabies lapsed before booster",
+			asOf: time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC),
+			expected: []VaccineStatus{
+				{VaccineType: "FVRCP", VaccinationID: "vaccination-cmd-3", Current: true},
+				{VaccineType: "rabies", VaccinationID: "vaccination-cmd-2", Current: false},
+			},
+		},
+		{
+			name: "booster makes rabies current",
+			asOf: time.Date(2026, time.February, 14, 12, 0, 0, 0, time.UTC),
+			expected: []VaccineStatus{
+				{VaccineType: "FVRCP", VaccinationID: "vaccination-cmd-3", Current: true},
+				{VaccineType: "rabies", VaccinationID: "vaccination-cmd-4", Current: true},
+			},
+		},
+		{
+			name: "last valid day is current",
+			asOf: time.Date(2025, time.March, 1, 23, 59, 0, 0, time.UTC),
+			expected: []VaccineStatus{
+				{VaccineType: "FVRCP", VaccinationID: "vaccination-cmd-3", Current: true},
+				{VaccineType: "rabies", VaccinationID: "vaccination-cmd-2", Current: true},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			statuses := projection.VaccinesAsOf("cat-1", tc.asOf)
+			if len(statuses) != len(tc.expected) {
+				t.Fatalf("expected %d statuses, got %d", len(tc.expected), len(statuses))
+			}
+			for index, expected := range tc.expected {
+				got := statuses[index]
+				if got.VaccineType != expected.VaccineType || got.VaccinationID != expected.VaccinationID || got.Current != expected.Current {
+					t.Fatalf("status %d = %+v, want %+v", index, got, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package catcare
+
+import (
+	"context"
+	"sync"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+type WeightEntry struct {
+	EntryID            string
+	At                 string
+	Grams              int
+	BodyConditionScore int
+	Notes              string
+	Corrected          bool
+}
+
+// WeightHistory keeps the effective weight entries per stream: corrections
+// replace values in place and retracted entries are dropped.
+type WeightHistory struct {
+	mu                sync.RWMutex
+	entriesByStream   map[string][]WeightEntry
+	lastStreamVersion map[string]int
+}
+
+func NewWeightHistory() *WeightHistory {
+	return &WeightHistory{
+		entriesByStream:   map[string][]WeightEntry{},
+		lastStreamVersion: map[string]int{},
+	}
+}
+
+func (p *WeightHistory) Apply(_ context.Context, streamID string, version int, event core.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lastVersion := p.lastStreamVersion[streamID]
+	if version <= lastVersion {
+		return nil
+	}
+
+	entries := p.entriesByStream[streamID]
+	switch ev := event.(type) {
+	case core.WeightLogged:
+		p.entriesByStream[streamID] = append(entries, WeightEntry{
+			EntryID:            ev.EntryID,
+			At:                 ev.At,
+			Grams:              ev.Grams,
+			BodyConditionScore: ev.BodyConditionScore,
+			Notes:              ev.Notes,
+		})
+	case core.WeightEntryCorrected:
+		for index := range entries {
+			if entries[index].EntryID == ev.EntryID {
+				entries[index].At = ev.At
+				entries[index].Grams = ev.Grams
+				entries[index].BodyConditionScore = ev.BodyConditionScore
+				entries[index].Notes = ev.Notes
+				entries[index].Corrected = true
+			}
+		}
+	case core.WeightEntryRetracted:
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.EntryID != ev.EntryID {
+				kept = append(kept, entry)
+			}
+		}
+		p.entriesByStream[streamID] = kept
+	}
+
+	p.lastStreamVersion[streamID] = version
+	return nil
+}
+
+func (p *WeightHistory) ListWeightEntries(streamID string) []WeightEntry {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]WeightEntry(nil), p.entriesByStream[streamID]...)
+}
@@ -0,0 +1,36 @@
+package catcare
+
+import (
+	"context"
+	"testing"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func TestListWeightEntriesGivenCorrectedAndRetractedEntriesWhenListThenReturnsEffectiveHistory(t *testing.T) {
+	projection := NewWeightHistory()
+
+	events := []core.Event{
+		core.WeightLogged{CommandID: "cmd-2", EntryID: "weight-cmd-2", At: "2026-02-14T10:00:00Z", Grams: 42000, BodyConditionScore: 4},
+		core.WeightLogged{CommandID: "cmd-3", EntryID: "weight-cmd-3", At: "2026-02-15T10:00:00Z", Grams: 4150},
+		core.WeightLogged{CommandID: "cmd-4", EntryID: "weight-cmd-4", At: "2026-02-15T10:05:00Z", Grams: 4150},
+		core.WeightEntryCorrected{CommandID: "cmd-5", EntryID: "weight-cmd-2", At: "2026-02-14T10:00:00Z", Grams: 4200, BodyConditionScore: 5},
+		core.WeightEntryRetracted{CommandID: "cmd-6", EntryID: "weight-cmd-4", Reason: "duplicate"},
+	}
+	for index, event := range events {
+		if err := projection.Apply(context.Background(), "cat-1", index+2, event); err != nil {
+			t.Fatalf("apply event: %v", err)
+		}
+	}
+
+	entries := projection.ListWeightEntries("cat-1")
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].EntryID != "weight-cmd-2" || entries[0].Grams != 4200 || entries[0].BodyConditionScore != 5 || !entries[0].Corrected {
+		t.Fatalf("unexpected corrected entry %+v", entries[0])
+	}
+	if entries[1].EntryID != "weight-cmd-3" || entries[1].Corrected {
+		t.Fatalf("unexpected entry %+v", entries[1])
+	}
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Codec turns domain events into the (type, schema version, payload) rows a
+// store persists and back. Stores take a Codec at construction so they never
+// need to know the event catalog of the domains they serve. Decode must accept
+// every schema version Encode ever produced for a type.
+type Codec interface {
+	Encode(event any) (eventType string, schemaVersion int, payload []byte, err error)
+	Decode(eventType string, schemaVersion int, payload []byte) (any, error)
+}
+
+// Upcaster lifts the JSON object of a payload by one schema version, in
+// place: renaming, deriving or defaulting fields.
+type Upcaster func(fields map[string]json.RawMessage) error
+
+// EventType registers one event: the stable Name it is persisted under, the
+// SchemaVersion of its current payload shape, and New, which returns a zero
+// value of the event. Upcasters[i] lifts a payload from version i+1 to i+2,
+// so there is exactly one per past version and history is never rewritten.
+type EventType struct {
+	Name          string
+	SchemaVersion int
+	New           func() any
+	Upcasters     []Upcaster
+}
+
+// Registry is a JSON Codec over registered event types. Several domains can
+// register into one Registry and share a store, as long as names are unique.
+type Registry struct {
+	byName map[string]EventType
+	byType map[reflect.Type]EventType
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: map[string]EventType{},
+		byType: map[reflect.Type]EventType{},
+	}
+}
+
+func (r *Registry) Register(eventType EventType) error {
+	if eventType.Name == "" {
+		return fmt.Errorf("event type name is required")
+	}
+	if eventType.SchemaVersion < 1 {
+		return fmt.Errorf("event type %q: schema version must be at least 1", eventType.Name)
+	}
+	if len(eventType.Upcasters) != eventType.SchemaVersion-1 {
+		return fmt.Errorf("event type %q: schema version %d needs %d upcasters, got %d", eventType.Name, eventType.SchemaVersion, eventType.SchemaVersion-1, len(eventType.Upcasters))
+	}
+	if eventType.New == nil {
+		return fmt.Errorf("event type %q: factory is required", eventType.Name)
+	}
+	if _, exists := r.byName[eventType.Name]; exists {
+		return fmt.Errorf("event type %q already registered", eventType.Name)
+	}
+	goType := reflect.TypeOf(eventType.New())
+	if goType == nil {
+		return fmt.Errorf("event type %q: factory returned nil", eventType.Name)
+	}
+	if existing, exists := r.byType[goType]; exists {
+		return fmt.Errorf("event type %q: %s already registered as %q", eventType.Name, goType, existing.Name)
+	}
+
+	r.byName[eventType.Name] = eventType
+	r.byType[goType] = eventType
+	return nil
+}
+
+// Lookup returns the registration for a persisted event type name.
+func (r *Registry) Lookup(name string) (EventType, bool) {
+	eventType, ok := r.byName[name]
+	return eventType, ok
+}
+
+// Encode always writes the current schema version of the event type.
+func (r *Registry) Encode(event any) (string, int, []byte, error) {
+	eventType, ok := r.byType[reflect.TypeOf(event)]
+	if !ok {
+		return "", 0, nil, fmt.Errorf("unsupported event type %T", event)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	return eventType.Name, eventType.SchemaVersion, payload, nil
+}
+
+// Decode runs the upcasters from schemaVersion up to the current version
+// before unmarshalling. Payloads from a newer schema than the registered one
+// are refused rather than decoded lossily.
+func (r *Registry) Decode(name string, schemaVersion int, payload []byte) (any, error) {
+	eventType, ok := r.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported event type %q", name)
+	}
+	if schemaVersion < 1 || schemaVersion > eventType.SchemaVersion {
+		return nil, fmt.Errorf("decode %s: unsupported schema version %d (current %d)", name, schemaVersion, eventType.SchemaVersion)
+	}
+	if schemaVersion < eventType.SchemaVersion {
+		upcasted, err := upcast(payload, eventType.Upcasters[schemaVersion-1:])
+		if err != nil {
+			return nil, fmt.Errorf("upcast %s from schema version %d: %w", name, schemaVersion, err)
+		}
+		payload = upcasted
+	}
+	target := reflect.New(reflect.TypeOf(eventType.New()))
+	if err := json.Unmarshal(payload, target.Interface()); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", name, err)
+	}
+	return target.Elem().Interface(), nil
+}
+
+func upcast(payload []byte, upcasters []Upcaster) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = map[string]json.RawMessage{}
+	}
+	for _, upcaster := range upcasters {
+		if err := upcaster(fields); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func TestRegistryGivenRegisteredEventWhenEncodeAndDecodeThenRoundTrips(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(EventType{Name: "VetVisitRecorded", SchemaVersion: 1, New: func() any { return core.VetVisitRecorded{} }}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	visit := core.VetVisitRecorded{CommandID: "cmd-2", VisitID: "visit-cmd-2", DiagnosisCodes: []string{"otitis"}}
+	name, schemaVersion, payload, err := registry.Encode(visit)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if name != "VetVisitRecorded" || schemaVersion != 1 {
+		t.Fatalf("encoded as %q v%d, want VetVisitRecorded v1", name, schemaVersion)
+	}
+
+	decoded, err := registry.Decode(name, schemaVersion, payload)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	got, ok := decoded.(core.VetVisitRecorded)
+	if !ok {
+		t.Fatalf("decoded type = %T, want core.VetVisitRecorded", decoded)
+	}
+	if got.VisitID != visit.VisitID || len(got.DiagnosisCodes) != 1 || got.DiagnosisCodes[0] != "otitis" {
+		t.Fatalf("decoded = %+v, want %+v", got, visit)
+	}
+}
+
+func TestRegistryGivenUnknownEventWhenEncodeOrDecodeThenFails(t *testing.T) {
+	registry := NewRegistry()
+
+	if _, _, _, err := registry.Encode(core.CatRegistered{}); err == nil {
+		t.Fatalf("expected encode of unregistered type to fail")
+	}
+	if _, err := registry.Decode("CatRegistered", 1, []byte(`{}`)); err == nil {
+		t.Fatalf("expected decode of unregistered name to fail")
+	}
+}
+
+func TestRegistryGivenInvalidRegistrationWhenRegisterThenFails(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(EventType{Name: "CatRegistered", SchemaVersion: 1, New: func() any { return core.CatRegistered{} }}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		eventType EventType
+	}{
+		{name: "empty name", eventType: EventType{SchemaVersion: 1, New: func() any { return core.CatRenamed{} }}},
+		{name: "schema version below one", eventType: EventType{Name: "CatRenamed", New: func() any { return core.CatRenamed{} }}},
+		{name: "missing factory", eventType: EventType{Name: "CatRenamed", SchemaVersion: 1}},
+		{name: "duplicate name", eventType: EventType{Name: "CatRegistered", SchemaVersion: 1, New: func() any { return core.CatRenamed{} }}},
+		{name: "duplicate go type", eventType: EventType{Name: "CatAdopted", SchemaVersion: 1, New: func() any { return core.CatRegistered{} }}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := registry.Register(tc.eventType); err == nil {
+				t.Fatalf("expected registration to fail")
+			}
+		})
+	}
+}
@@ -2,5 +2,8 @@ package store
 
 import "errors"
 
-var ErrConcurrencyConflict = errors.New("concurrency conflict")
+var (
+	ErrConcurrencyConflict = errors.New("concurrency conflict")
+	ErrAlreadyReserved     = errors.New("already reserved by another stream")
+)
 
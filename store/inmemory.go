@@ -9,10 +9,16 @@ type InMemoryStore struct {
 	mu        sync.Mutex
 	streams   map[string]*eventStream
 	snapshots map[string]Snapshot
+	reserved  map[reservationKey]string
+}
+
+type reservationKey struct {
+	scope string
+	value string
 }
 
 type eventStream struct {
-	events  []any
+	records []RecordedEvent
 	version int
 }
 
@@ -20,10 +26,15 @@ func NewInMemoryStore() *InMemoryStore {
 	return &InMemoryStore{
 		streams:   map[string]*eventStream{},
 		snapshots: map[string]Snapshot{},
+		reserved:  map[reservationKey]string{},
 	}
 }
 
-func (s *InMemoryStore) Load(ctx context.Context, streamID string) ([]any, int, error) {
+func (s *InMemoryStore) Load(ctx context.Context, streamID string) ([]RecordedEvent, int, error) {
+	return s.LoadFrom(ctx, streamID, 1, 0)
+}
+
+func (s *InMemoryStore) LoadFrom(ctx context.Context, streamID string, fromVersion int, limit int) ([]RecordedEvent, int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -31,11 +42,23 @@ func (s *InMemoryStore) Load(ctx context.Context, streamID string) ([]any, int,
 	if !exists {
 		return nil, 0, nil
 	}
-	events := append([]any(nil), stream.events...)
-	return events, stream.version, nil
+	start := max(fromVersion, 1) - 1
+	if start >= len(stream.records) {
+		return nil, stream.version, nil
+	}
+	end := len(stream.records)
+	if limit > 0 {
+		end = min(end, start+limit)
+	}
+	records := append([]RecordedEvent(nil), stream.records[start:end]...)
+	return records, stream.version, nil
+}
+
+func (s *InMemoryStore) Append(ctx context.Context, streamID string, expectedVersion int, events []any, metadata Metadata) (int, error) {
+	return s.AppendReserving(ctx, streamID, expectedVersion, events, metadata, nil, nil)
 }
 
-func (s *InMemoryStore) Append(ctx context.Context, streamID string, expectedVersion int, events []any) (int, error) {
+func (s *InMemoryStore) AppendReserving(ctx context.Context, streamID string, expectedVersion int, events []any, metadata Metadata, claim []Reservation, release []Reservation) (int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -48,13 +71,26 @@ func (s *InMemoryStore) Append(ctx context.Context, streamID string, expectedVer
 	if expectedVersion != stream.version {
 		return stream.version, ErrConcurrencyConflict
 	}
-
-	if len(events) == 0 {
-		return stream.version, nil
+	for _, reservation := range claim {
+		key := reservationKey{scope: reservation.Scope, value: reservation.Value}
+		if owner, exists := s.reserved[key]; exists && owner != streamID {
+			return stream.version, &ReservedError{Reservation: reservation, Owner: owner}
+		}
 	}
 
-	stream.events = append(stream.events, events...)
-	stream.version += len(events)
+	for _, reservation := range release {
+		key := reservationKey{scope: reservation.Scope, value: reservation.Value}
+		if s.reserved[key] == streamID {
+			delete(s.reserved, key)
+		}
+	}
+	for _, reservation := range claim {
+		s.reserved[reservationKey{scope: reservation.Scope, value: reservation.Value}] = streamID
+	}
+	for _, event := range events {
+		stream.version++
+		stream.records = append(stream.records, RecordedEvent{Version: stream.version, Event: event, Metadata: metadata})
+	}
 	return stream.version, nil
 }
 
@@ -70,7 +106,10 @@ func (s *InMemoryStore) SaveSnapshot(ctx context.Context, streamID string, snaps
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if current, exists := s.snapshots[streamID]; exists && current.Version >= snapshot.Version {
+		return nil
+	}
+	snapshot.State = append([]byte(nil), snapshot.State...)
 	s.snapshots[streamID] = snapshot
 	return nil
 }
-
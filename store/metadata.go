@@ -0,0 +1,38 @@
+package store
+
+import "time"
+
+// Actor types of the command envelope.
+const (
+	ActorAI     = "ai"
+	ActorHuman  = "human"
+	ActorSystem = "system"
+)
+
+// Actor is who issued the command that produced an event.
+type Actor struct {
+	Type string
+	ID   string
+}
+
+// Metadata is persisted alongside every event of one Append. It records the
+// append itself, not the domain fact: who asked for it, when the adapter
+// recorded it, the flow it belongs to (CorrelationID) and the message that
+// directly caused it (CausationID).
+type Metadata struct {
+	Actor         Actor
+	RecordedAt    time.Time
+	CorrelationID string
+	CausationID   string
+}
+
+// ValidActorType reports whether actorType is one of the envelope's actor
+// types.
+func ValidActorType(actorType string) bool {
+	switch actorType {
+	case ActorAI, ActorHuman, ActorSystem:
+		return true
+	default:
+		return false
+	}
+}
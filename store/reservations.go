@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reservation is a value that must be unique within its scope across
+// streams, such as a microchip number.
+type Reservation struct {
+	Scope string
+	Value string
+}
+
+// ReservedError reports a reservation already owned by another stream.
+type ReservedError struct {
+	Reservation Reservation
+	Owner       string
+}
+
+func (e *ReservedError) Error() string {
+	return fmt.Sprintf("%s %q already reserved by %s", e.Reservation.Scope, e.Reservation.Value, e.Owner)
+}
+
+func (e *ReservedError) Unwrap() error { return ErrAlreadyReserved }
+
+// ReservationStore claims values that must be unique across streams. A value
+// belongs to at most one stream per scope.
+type ReservationStore interface {
+	// AppendReserving is Append that also claims every reservation in claim
+	// for streamID and drops the ones in release that streamID owns, all in
+	// the same transaction as the events. Claiming a value the stream already
+	// owns is a no-op. A value owned by another stream fails with a
+	// *ReservedError and nothing is stored, so a failed append never leaves
+	// or removes a claim.
+	AppendReserving(ctx context.Context, streamID string, expectedVersion int, events []any, metadata Metadata, claim []Reservation, release []Reservation) (newVersion int, err error)
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func TestAppendReservingGivenClaimedValueWhenAppendsConflictThenKeepsTheOwnersClaim(t *testing.T) {
+	chip := Reservation{Scope: "microchip", Value: "985112003456789"}
+	registered := []any{core.MicrochipRegistered{CommandID: "chip-1", ChipNumber: chip.Value}}
+
+	for name, events := range storesForTest(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			reserver := events.(ReservationStore)
+
+			if _, err := reserver.AppendReserving(ctx, "cat-1", 0, registered, Metadata{}, []Reservation{chip}, nil); err != nil {
+				t.Fatalf("claim for cat-1: %v", err)
+			}
+			if _, err := reserver.AppendReserving(ctx, "cat-1", 1, nil, Metadata{}, []Reservation{chip}, nil); err != nil {
+				t.Fatalf("claim again for the owner: %v", err)
+			}
+			if _, err := reserver.AppendReserving(ctx, "cat-1", 0, registered, Metadata{}, []Reservation{chip}, nil); err != ErrConcurrencyConflict {
+				t.Fatalf("stale append err = %v, want %v", err, ErrConcurrencyConflict)
+			}
+
+			_, err := reserver.AppendReserving(ctx, "cat-2", 0, registered, Metadata{}, []Reservation{chip}, nil)
+			var reserved *ReservedError
+			if !errors.As(err, &reserved) || !errors.Is(err, ErrAlreadyReserved) || reserved.Owner != "cat-1" || reserved.Reservation != chip {
+				t.Fatalf("claim for cat-2 err = %v, want chip reserved by cat-1", err)
+			}
+			if _, version, err := events.LoadFrom(ctx, "cat-2", 1, 0); err != nil || version != 0 {
+				t.Fatalf("cat-2 version = %d, err %v, want nothing stored", version, err)
+			}
+
+			if _, err := reserver.AppendReserving(ctx, "cat-2", 0, nil, Metadata{}, nil, []Reservation{chip}); err != nil {
+				t.Fatalf("release by non-owner: %v", err)
+			}
+			if _, err := reserver.AppendReserving(ctx, "cat-1", 1, nil, Metadata{}, nil, []Reservation{chip}); err != nil {
+				t.Fatalf("release by owner: %v", err)
+			}
+			if _, err := reserver.AppendReserving(ctx, "cat-2", 0, registered, Metadata{}, []Reservation{chip}, nil); err != nil {
+				t.Fatalf("claim for cat-2 after release: %v", err)
+			}
+		})
+	}
+}
@@ -2,13 +2,18 @@ package store
 
 import "context"
 
+// Snapshot is the state of a stream as of Version, serialized by its owner.
+// Stores keep State as opaque bytes; a snapshot is a cache and can always be
+// rebuilt by replaying the stream.
 type Snapshot struct {
 	Version int
-	State   any
+	State   []byte
 }
 
 type SnapshotStore interface {
 	LoadSnapshot(ctx context.Context, streamID string) (snapshot Snapshot, ok bool, err error)
+	// SaveSnapshot replaces the stream's snapshot unless the stored one is
+	// at the same or a later version.
 	SaveSnapshot(ctx context.Context, streamID string, snapshot Snapshot) error
 }
 
@@ -3,34 +3,60 @@ package store
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
-	core "github.com/wastingnotime/zeroapps/core/catcare"
 	_ "modernc.org/sqlite"
 )
 
+// SQLiteStore persists event streams in a single SQLite file. Event payloads
+// go through the Codec it was built with, so every domain registered in that
+// codec can share the file.
 type SQLiteStore struct {
-	db *sql.DB
+	db    *sql.DB
+	codec Codec
 }
 
 type sqliteEventRow struct {
-	Version int
-	Type    string
-	Payload string
+	Version       int
+	Type          string
+	SchemaVersion int
+	Payload       string
+	ActorType     string
+	ActorID       string
+	RecordedAt    string
+	CorrelationID string
+	CausationID   string
 }
 
-type sqliteProjectionApplier interface {
-	Apply(ctx context.Context, streamID string, version int, event core.Event) error
+// eventColumns are the events columns added after the first release, with
+// the definition that upgrades older files. Rows written before a column
+// existed read its default: the first schema version and empty metadata.
+var eventColumns = []struct {
+	name       string
+	definition string
+}{
+	{name: "schema_version", definition: "INTEGER NOT NULL DEFAULT 1"},
+	{name: "actor_type", definition: "TEXT NOT NULL DEFAULT ''"},
+	{name: "actor_id", definition: "TEXT NOT NULL DEFAULT ''"},
+	{name: "recorded_at", definition: "TEXT NOT NULL DEFAULT ''"},
+	{name: "correlation_id", definition: "TEXT NOT NULL DEFAULT ''"},
+	{name: "causation_id", definition: "TEXT NOT NULL DEFAULT ''"},
 }
 
-func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
+// ReplayFunc receives every stored event during Replay.
+type ReplayFunc func(ctx context.Context, streamID string, recorded RecordedEvent) error
+
+func NewSQLiteStore(dbPath string, codec Codec) (*SQLiteStore, error) {
 	if dbPath == "" {
 		return nil, fmt.Errorf("db path is required")
 	}
+	if codec == nil {
+		return nil, fmt.Errorf("codec is required")
+	}
 
 	if dbPath != ":memory:" {
 		if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
@@ -43,7 +69,7 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		return nil, err
 	}
 
-	store := &SQLiteStore{db: db}
+	store := &SQLiteStore{db: db, codec: codec}
 	if err := store.initSchema(context.Background()); err != nil {
 		_ = db.Close()
 		return nil, err
@@ -71,61 +97,121 @@ CREATE TABLE IF NOT EXISTS events (
 	version INTEGER NOT NULL,
 	event_type TEXT NOT NULL,
 	payload TEXT NOT NULL,
+	schema_version INTEGER NOT NULL DEFAULT 1,
+	actor_type TEXT NOT NULL DEFAULT '',
+	actor_id TEXT NOT NULL DEFAULT '',
+	recorded_at TEXT NOT NULL DEFAULT '',
+	correlation_id TEXT NOT NULL DEFAULT '',
+	causation_id TEXT NOT NULL DEFAULT '',
 	PRIMARY KEY (stream_id, version)
 );
 
 CREATE INDEX IF NOT EXISTS idx_events_stream_version
 ON events(stream_id, version);
+
+CREATE TABLE IF NOT EXISTS snapshots (
+	stream_id TEXT PRIMARY KEY,
+	version INTEGER NOT NULL,
+	state BLOB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+	scope TEXT NOT NULL,
+	value TEXT NOT NULL,
+	stream_id TEXT NOT NULL,
+	PRIMARY KEY (scope, value)
+);
 `
-	_, err := s.db.ExecContext(ctx, ddl)
-	return err
+	if _, err := s.db.ExecContext(ctx, ddl); err != nil {
+		return err
+	}
+	return s.addEventColumns(ctx)
 }
 
-func (s *SQLiteStore) Load(ctx context.Context, streamID string) ([]any, int, error) {
+// addEventColumns adds the eventColumns a file does not have yet.
+func (s *SQLiteStore) addEventColumns(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT name FROM pragma_table_info('events')`)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		existing[column] = true
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, column := range eventColumns {
+		if existing[column.name] {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, "ALTER TABLE events ADD COLUMN "+column.name+" "+column.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Load(ctx context.Context, streamID string) ([]RecordedEvent, int, error) {
+	return s.LoadFrom(ctx, streamID, 1, 0)
+}
+
+func (s *SQLiteStore) LoadFrom(ctx context.Context, streamID string, fromVersion int, limit int) ([]RecordedEvent, int, error) {
 	version, err := s.streamVersion(ctx, streamID)
 	if err != nil {
 		return nil, 0, err
 	}
+	if limit <= 0 {
+		limit = -1
+	}
 
 	rows, err := s.db.QueryContext(ctx, `
-SELECT version, event_type, payload
+SELECT version, event_type, schema_version, payload, actor_type, actor_id, recorded_at, correlation_id, causation_id
 FROM events
-WHERE stream_id = ?
+WHERE stream_id = ? AND version >= ?
 ORDER BY version ASC
-`, streamID)
+LIMIT ?
+`, streamID, fromVersion, limit)
 	if err != nil {
 		return nil, 0, err
 	}
 	defer rows.Close()
 
-	events := make([]any, 0)
+	records := make([]RecordedEvent, 0)
 	for rows.Next() {
-		var row sqliteEventRow
-		if err := rows.Scan(&row.Version, &row.Type, &row.Payload); err != nil {
-			return nil, 0, err
-		}
-		event, err := decodeCatCareEvent(row.Type, row.Payload)
+		recorded, err := s.scanRecordedEvent(rows.Scan)
 		if err != nil {
 			return nil, 0, err
 		}
-		events = append(events, event)
+		records = append(records, recorded)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, 0, err
 	}
 
-	return events, version, nil
+	return records, version, nil
+}
+
+func (s *SQLiteStore) Append(ctx context.Context, streamID string, expectedVersion int, events []any, metadata Metadata) (int, error) {
+	return s.AppendReserving(ctx, streamID, expectedVersion, events, metadata, nil, nil)
 }
 
-func (s *SQLiteStore) Append(ctx context.Context, streamID string, expectedVersion int, events []any) (int, error) {
+func (s *SQLiteStore) AppendReserving(ctx context.Context, streamID string, expectedVersion int, events []any, metadata Metadata, claim []Reservation, release []Reservation) (int, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
+		_ = tx.Rollback()
 	}()
 
 	currentVersion, err := s.streamVersionTx(ctx, tx, streamID)
@@ -135,37 +221,47 @@ func (s *SQLiteStore) Append(ctx context.Context, streamID string, expectedVersi
 	if expectedVersion != currentVersion {
 		return currentVersion, ErrConcurrencyConflict
 	}
-	if len(events) == 0 {
-		if err := tx.Commit(); err != nil {
+
+	for _, reservation := range release {
+		if _, err := tx.ExecContext(ctx, `
+DELETE FROM reservations WHERE scope = ? AND value = ? AND stream_id = ?
+`, reservation.Scope, reservation.Value, streamID); err != nil {
 			return 0, err
 		}
-		return currentVersion, nil
+	}
+	for _, reservation := range claim {
+		if err := reserveTx(ctx, tx, reservation, streamID); err != nil {
+			return currentVersion, err
+		}
 	}
 
+	recordedAt := ""
+	if !metadata.RecordedAt.IsZero() {
+		recordedAt = metadata.RecordedAt.UTC().Format(time.RFC3339Nano)
+	}
 	for index, rawEvent := range events {
-		event, ok := rawEvent.(core.Event)
-		if !ok {
-			return 0, fmt.Errorf("unexpected event type %T", rawEvent)
-		}
-		eventType, payload, err := encodeCatCareEvent(event)
+		eventType, schemaVersion, payload, err := s.codec.Encode(rawEvent)
 		if err != nil {
 			return 0, err
 		}
 		eventVersion := currentVersion + index + 1
 		if _, err := tx.ExecContext(ctx, `
-INSERT INTO events(stream_id, version, event_type, payload)
-VALUES(?, ?, ?, ?)
-`, streamID, eventVersion, eventType, payload); err != nil {
+INSERT INTO events(stream_id, version, event_type, schema_version, payload, actor_type, actor_id, recorded_at, correlation_id, causation_id)
+VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`, streamID, eventVersion, eventType, schemaVersion, string(payload),
+			metadata.Actor.Type, metadata.Actor.ID, recordedAt, metadata.CorrelationID, metadata.CausationID); err != nil {
 			return 0, err
 		}
 	}
 
 	newVersion := currentVersion + len(events)
-	if _, err := tx.ExecContext(ctx, `
+	if len(events) > 0 {
+		if _, err := tx.ExecContext(ctx, `
 INSERT INTO streams(stream_id, version) VALUES(?, ?)
 ON CONFLICT(stream_id) DO UPDATE SET version = excluded.version
 `, streamID, newVersion); err != nil {
-		return 0, err
+			return 0, err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -175,9 +271,33 @@ ON CONFLICT(stream_id) DO UPDATE SET version = excluded.version
 	return newVersion, nil
 }
 
-func (s *SQLiteStore) Replay(ctx context.Context, projector sqliteProjectionApplier) error {
+// reserveTx claims reservation for streamID inside tx. The primary key on
+// (scope, value) makes the claim atomic across processes sharing the file.
+func reserveTx(ctx context.Context, tx *sql.Tx, reservation Reservation, streamID string) error {
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO reservations(scope, value, stream_id) VALUES(?, ?, ?)
+ON CONFLICT(scope, value) DO NOTHING
+`, reservation.Scope, reservation.Value, streamID); err != nil {
+		return err
+	}
+
+	var owner string
+	if err := tx.QueryRowContext(ctx, `
+SELECT stream_id FROM reservations WHERE scope = ? AND value = ?
+`, reservation.Scope, reservation.Value).Scan(&owner); err != nil {
+		return err
+	}
+	if owner != streamID {
+		return &ReservedError{Reservation: reservation, Owner: owner}
+	}
+	return nil
+}
+
+// Replay decodes every stored event with its metadata, ordered by stream and
+// version, and hands it to apply.
+func (s *SQLiteStore) Replay(ctx context.Context, apply ReplayFunc) error {
 	rows, err := s.db.QueryContext(ctx, `
-SELECT stream_id, version, event_type, payload
+SELECT stream_id, version, event_type, schema_version, payload, actor_type, actor_id, recorded_at, correlation_id, causation_id
 FROM events
 ORDER BY stream_id ASC, version ASC
 `)
@@ -188,17 +308,13 @@ ORDER BY stream_id ASC, version ASC
 
 	for rows.Next() {
 		var streamID string
-		var version int
-		var eventType string
-		var payload string
-		if err := rows.Scan(&streamID, &version, &eventType, &payload); err != nil {
-			return err
-		}
-		event, err := decodeCatCareEvent(eventType, payload)
+		recorded, err := s.scanRecordedEvent(func(dest ...any) error {
+			return rows.Scan(append([]any{&streamID}, dest...)...)
+		})
 		if err != nil {
 			return err
 		}
-		if err := projector.Apply(ctx, streamID, version, event); err != nil {
+		if err := apply(ctx, streamID, recorded); err != nil {
 			return err
 		}
 	}
@@ -206,6 +322,62 @@ ORDER BY stream_id ASC, version ASC
 	return rows.Err()
 }
 
+func (s *SQLiteStore) LoadSnapshot(ctx context.Context, streamID string) (Snapshot, bool, error) {
+	var snapshot Snapshot
+	err := s.db.QueryRowContext(ctx, `
+SELECT version, state FROM snapshots WHERE stream_id = ?
+`, streamID).Scan(&snapshot.Version, &snapshot.State)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	return snapshot, true, nil
+}
+
+// SaveSnapshot keeps one snapshot per stream. A snapshot older than the one
+// stored, e.g. from a slower concurrent writer, is dropped.
+func (s *SQLiteStore) SaveSnapshot(ctx context.Context, streamID string, snapshot Snapshot) error {
+	_, err := s.db.ExecContext(ctx, `
+INSERT INTO snapshots(stream_id, version, state) VALUES(?, ?, ?)
+ON CONFLICT(stream_id) DO UPDATE SET version = excluded.version, state = excluded.state
+WHERE excluded.version > snapshots.version
+`, streamID, snapshot.Version, snapshot.State)
+	return err
+}
+
+// scanRecordedEvent reads the event columns selected by LoadFrom and Replay
+// and decodes the payload.
+func (s *SQLiteStore) scanRecordedEvent(scan func(dest ...any) error) (RecordedEvent, error) {
+	var row sqliteEventRow
+	if err := scan(&row.Version, &row.Type, &row.SchemaVersion, &row.Payload,
+		&row.ActorType, &row.ActorID, &row.RecordedAt, &row.CorrelationID, &row.CausationID); err != nil {
+		return RecordedEvent{}, err
+	}
+	event, err := s.codec.Decode(row.Type, row.SchemaVersion, []byte(row.Payload))
+	if err != nil {
+		return RecordedEvent{}, err
+	}
+	var recordedAt time.Time
+	if row.RecordedAt != "" {
+		recordedAt, err = time.Parse(time.RFC3339Nano, row.RecordedAt)
+		if err != nil {
+			return RecordedEvent{}, fmt.Errorf("event %s v%d: recorded_at: %w", row.Type, row.Version, err)
+		}
+	}
+	return RecordedEvent{
+		Version: row.Version,
+		Event:   event,
+		Metadata: Metadata{
+			Actor:         Actor{Type: row.ActorType, ID: row.ActorID},
+			RecordedAt:    recordedAt,
+			CorrelationID: row.CorrelationID,
+			CausationID:   row.CausationID,
+		},
+	}, nil
+}
+
 func (s *SQLiteStore) streamVersion(ctx context.Context, streamID string) (int, error) {
 	var version int
 	err := s.db.QueryRowContext(ctx, `
@@ -227,35 +399,3 @@ SELECT version FROM streams WHERE stream_id = ?
 	}
 	return version, err
 }
-
-func encodeCatCareEvent(event core.Event) (string, string, error) {
-	switch ev := event.(type) {
-	case core.CatRegistered:
-		payload, err := json.Marshal(ev)
-		return "CatRegistered", string(payload), err
-	case core.WeightLogged:
-		payload, err := json.Marshal(ev)
-		return "WeightLogged", string(payload), err
-	default:
-		return "", "", fmt.Errorf("unsupported event type %T", event)
-	}
-}
-
-func decodeCatCareEvent(eventType string, payload string) (core.Event, error) {
-	switch eventType {
-	case "CatRegistered":
-		var event core.CatRegistered
-		if err := json.Unmarshal([]byte(payload), &event); err != nil {
-			return nil, err
-		}
-		return event, nil
-	case "WeightLogged":
-		var event core.WeightLogged
-		if err := json.Unmarshal([]byte(payload), &event); err != nil {
-			return nil, err
-		}
-		return event, nil
-	default:
-		return nil, fmt.Errorf("unsupported event type %q", eventType)
-	}
-}
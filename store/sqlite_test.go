@@ -2,10 +2,13 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/core/household"
 )
 
 func TestSQLiteStoreGivenAppendedEventsWhenLoadThenReturnsEventsAndVersion(t *testing.T) {
@@ -20,7 +23,7 @@ func TestSQLiteStoreGivenAppendedEventsWhenLoadThenReturnsEventsAndVersion(t *te
 		CatID:     "cat-cmd-1",
 		Name:      "Miso",
 		BirthDate: "2023-01-01",
-	}})
+	}}, Metadata{})
 	if err != nil {
 		t.Fatalf("append: %v", err)
 	}
@@ -28,19 +31,19 @@ func TestSQLiteStoreGivenAppendedEventsWhenLoadThenReturnsEventsAndVersion(t *te
 		t.Fatalf("newVersion = %d, want 1", newVersion)
 	}
 
-	events, version, err := store.Load(ctx, "cat-cmd-1")
+	records, version, err := store.Load(ctx, "cat-cmd-1")
 	if err != nil {
 		t.Fatalf("load: %v", err)
 	}
 	if version != 1 {
 		t.Fatalf("version = %d, want 1", version)
 	}
-	if len(events) != 1 {
-		t.Fatalf("len(events) = %d, want 1", len(events))
+	if len(records) != 1 {
+		t.Fatalf("len(records) = %d, want 1", len(records))
 	}
-	registered, ok := events[0].(core.CatRegistered)
+	registered, ok := records[0].Event.(core.CatRegistered)
 	if !ok {
-		t.Fatalf("event type = %T, want core.CatRegistered", events[0])
+		t.Fatalf("event type = %T, want core.CatRegistered", records[0].Event)
 	}
 	if registered.CatID != "cat-cmd-1" {
 		t.Fatalf("cat_id = %q, want cat-cmd-1", registered.CatID)
@@ -59,7 +62,7 @@ func TestSQLiteStoreGivenWrongExpectedVersionWhenAppendThenReturnsConcurrencyCon
 		CatID:     "cat-cmd-1",
 		Name:      "Miso",
 		BirthDate: "2023-01-01",
-	}})
+	}}, Metadata{})
 	if err != nil {
 		t.Fatalf("initial append: %v", err)
 	}
@@ -69,18 +72,118 @@ func TestSQLiteStoreGivenWrongExpectedVersionWhenAppendThenReturnsConcurrencyCon
 		EntryID:   "weight-cmd-2",
 		At:        "2026-02-14T10:00:00Z",
 		Grams:     4200,
-	}})
+	}}, Metadata{})
 	if err != ErrConcurrencyConflict {
 		t.Fatalf("err = %v, want %v", err, ErrConcurrencyConflict)
 	}
 }
 
+func TestSQLiteStoreGivenTwoDomainsWhenReplayThenDeliversEveryEventInStreamOrder(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteStoreForTest(t)
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	created := household.HouseholdCreated{CommandID: "cmd-1", HouseholdID: "household-cmd-1", Name: "Casa", OwnerRef: "person-ana"}
+	added := household.CatAddedToHousehold{CommandID: "cmd-2", HouseholdID: "household-cmd-1", CatID: "cat-cmd-1"}
+	if _, err := store.Append(ctx, "household-cmd-1", 0, []any{created, added}, Metadata{}); err != nil {
+		t.Fatalf("append household: %v", err)
+	}
+	if _, err := store.Append(ctx, "cat-cmd-1", 0, []any{core.CatRegistered{CommandID: "cmd-1", CatID: "cat-cmd-1", Name: "Miso"}}, Metadata{}); err != nil {
+		t.Fatalf("append cat: %v", err)
+	}
+
+	records, version, err := store.Load(ctx, "household-cmd-1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if version != 2 || records[0].Event != any(created) || records[1].Event != any(added) {
+		t.Fatalf("loaded %v at version %d", records, version)
+	}
+
+	var replayed []string
+	err = store.Replay(ctx, func(_ context.Context, streamID string, recorded RecordedEvent) error {
+		replayed = append(replayed, fmt.Sprintf("%s@%d:%T", streamID, recorded.Version, recorded.Event))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	want := []string{
+		"cat-cmd-1@1:catcare.CatRegistered",
+		"household-cmd-1@1:household.HouseholdCreated",
+		"household-cmd-1@2:household.CatAddedToHousehold",
+	}
+	if strings.Join(replayed, " ") != strings.Join(want, " ") {
+		t.Fatalf("replayed = %v, want %v", replayed, want)
+	}
+}
+
+func TestSQLiteStoreGivenSnapshotsWhenSaveAndLoadThenKeepsTheLatest(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "catcare.db")
+	store, err := NewSQLiteStore(dbPath, newTestCodec(t))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+
+	if _, ok, err := store.LoadSnapshot(ctx, "cat-cmd-1"); err != nil || ok {
+		t.Fatalf("load missing snapshot: ok = %t, err = %v", ok, err)
+	}
+	for _, snapshot := range []Snapshot{
+		{Version: 50, State: []byte(`{"v":50}`)},
+		{Version: 100, State: []byte(`{"v":100}`)},
+		{Version: 75, State: []byte(`{"v":75}`)},
+	} {
+		if err := store.SaveSnapshot(ctx, "cat-cmd-1", snapshot); err != nil {
+			t.Fatalf("save snapshot %d: %v", snapshot.Version, err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	reopened, err := NewSQLiteStore(dbPath, newTestCodec(t))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = reopened.Close()
+	})
+	snapshot, ok, err := reopened.LoadSnapshot(ctx, "cat-cmd-1")
+	if err != nil || !ok {
+		t.Fatalf("load snapshot: ok = %t, err = %v", ok, err)
+	}
+	if snapshot.Version != 100 || string(snapshot.State) != `{"v":100}` {
+		t.Fatalf("snapshot = %d %s, want 100 {\"v\":100}", snapshot.Version, snapshot.State)
+	}
+}
+
 func newSQLiteStoreForTest(t *testing.T) *SQLiteStore {
 	t.Helper()
 	dbPath := filepath.Join(t.TempDir(), "catcare.db")
-	store, err := NewSQLiteStore(dbPath)
+	store, err := NewSQLiteStore(dbPath, newTestCodec(t))
 	if err != nil {
 		t.Fatalf("NewSQLiteStore: %v", err)
 	}
 	return store
 }
+
+func newTestCodec(t *testing.T) *Registry {
+	t.Helper()
+	registry := NewRegistry()
+	for _, event := range core.EventTypes() {
+		zero := event
+		if err := registry.Register(EventType{Name: core.EventName(zero), SchemaVersion: 1, New: func() any { return zero }}); err != nil {
+			t.Fatalf("register %T: %v", zero, err)
+		}
+	}
+	for _, event := range household.EventTypes() {
+		zero := event
+		if err := registry.Register(EventType{Name: household.EventName(zero), SchemaVersion: 1, New: func() any { return zero }}); err != nil {
+			t.Fatalf("register %T: %v", zero, err)
+		}
+	}
+	return registry
+}
@@ -3,7 +3,15 @@ package store
 import "context"
 
 type EventStore interface {
-	Load(ctx context.Context, streamID string) (events []any, version int, err error)
-	Append(ctx context.Context, streamID string, expectedVersion int, events []any) (newVersion int, err error)
+	// Load returns every recorded event of the stream, with its metadata, and
+	// the current stream version. It is LoadFrom(ctx, streamID, 1, 0).
+	Load(ctx context.Context, streamID string) (records []RecordedEvent, version int, err error)
+	// LoadFrom returns at most limit recorded events of the stream starting
+	// at fromVersion (the first event is version 1), with their metadata and
+	// the current stream version. A limit of zero or less returns every
+	// remaining event.
+	LoadFrom(ctx context.Context, streamID string, fromVersion int, limit int) (records []RecordedEvent, version int, err error)
+	// Append stores events after expectedVersion, all with the same metadata.
+	Append(ctx context.Context, streamID string, expectedVersion int, events []any, metadata Metadata) (newVersion int, err error)
 }
 
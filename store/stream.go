@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is the number of events ReadStream loads per page when no
+// page size is given.
+const DefaultPageSize = 500
+
+// RecordedEvent is a stored event with its version in the stream and the
+// metadata of the append that stored it.
+type RecordedEvent struct {
+	Version  int
+	Event    any
+	Metadata Metadata
+}
+
+// ReadStream iterates over a stream from fromVersion onward, loading
+// pageSize events at a time, so callers can walk long streams without holding
+// them in memory. A load error is yielded once and ends the iteration.
+// Events appended while iterating are included.
+func ReadStream(ctx context.Context, events EventStore, streamID string, fromVersion int, pageSize int) iter.Seq2[RecordedEvent, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return func(yield func(RecordedEvent, error) bool) {
+		next := max(fromVersion, 1)
+		for {
+			page, version, err := events.LoadFrom(ctx, streamID, next, pageSize)
+			if err != nil {
+				yield(RecordedEvent{}, err)
+				return
+			}
+			for _, recorded := range page {
+				if !yield(recorded, nil) {
+					return
+				}
+				next = recorded.Version + 1
+			}
+			if len(page) < pageSize || next > version {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+)
+
+func storesForTest(t *testing.T) map[string]EventStore {
+	t.Helper()
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "catcare.db"), newTestCodec(t))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sqlite.Close()
+	})
+	return map[string]EventStore{"in-memory": NewInMemoryStore(), "sqlite": sqlite}
+}
+
+func appendWeights(t *testing.T, events EventStore, streamID string, count int) {
+	t.Helper()
+	batch := make([]any, 0, count)
+	for index := 1; index <= count; index++ {
+		batch = append(batch, core.WeightLogged{CommandID: fmt.Sprintf("cmd-%d", index), Grams: 4000 + index})
+	}
+	if _, err := events.Append(context.Background(), streamID, 0, batch, Metadata{}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+}
+
+func weightGrams(records []RecordedEvent) []int {
+	grams := make([]int, 0, len(records))
+	for _, recorded := range records {
+		grams = append(grams, recorded.Event.(core.WeightLogged).Grams)
+	}
+	return grams
+}
+
+func TestLoadFromGivenStreamWhenLoadingFromVersionThenReturnsThePageAndStreamVersion(t *testing.T) {
+	cases := []struct {
+		name        string
+		fromVersion int
+		limit       int
+		want        []int
+	}{
+		{name: "whole stream", fromVersion: 1, limit: 0, want: []int{4001, 4002, 4003, 4004, 4005}},
+		{name: "tail", fromVersion: 4, limit: 0, want: []int{4004, 4005}},
+		{name: "page", fromVersion: 2, limit: 2, want: []int{4002, 4003}},
+		{name: "limit past the end", fromVersion: 5, limit: 10, want: []int{4005}},
+		{name: "version before the first", fromVersion: 0, limit: 1, want: []int{4001}},
+		{name: "after the last", fromVersion: 6, limit: 0, want: []int{}},
+	}
+
+	for storeName, events := range storesForTest(t) {
+		appendWeights(t, events, "cat-cmd-1", 5)
+		for _, tc := range cases {
+			t.Run(storeName+"/"+tc.name, func(t *testing.T) {
+				loaded, version, err := events.LoadFrom(context.Background(), "cat-cmd-1", tc.fromVersion, tc.limit)
+				if err != nil {
+					t.Fatalf("load from: %v", err)
+				}
+				if version != 5 {
+					t.Fatalf("version = %d, want 5", version)
+				}
+				if got := weightGrams(loaded); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+					t.Fatalf("grams = %v, want %v", got, tc.want)
+				}
+			})
+		}
+	}
+}
+
+func TestReadStreamGivenLongStreamWhenIteratingInPagesThenYieldsEveryEventWithItsVersion(t *testing.T) {
+	for storeName, events := range storesForTest(t) {
+		t.Run(storeName, func(t *testing.T) {
+			appendWeights(t, events, "cat-cmd-1", 5)
+
+			var versions []int
+			for recorded, err := range ReadStream(context.Background(), events, "cat-cmd-1", 2, 2) {
+				if err != nil {
+					t.Fatalf("read stream: %v", err)
+				}
+				if recorded.Event.(core.WeightLogged).Grams != 4000+recorded.Version {
+					t.Fatalf("event %+v does not match version %d", recorded.Event, recorded.Version)
+				}
+				versions = append(versions, recorded.Version)
+			}
+			if fmt.Sprint(versions) != fmt.Sprint([]int{2, 3, 4, 5}) {
+				t.Fatalf("versions = %v, want [2 3 4 5]", versions)
+			}
+
+			var first []int
+			for recorded := range ReadStream(context.Background(), events, "cat-cmd-1", 1, 2) {
+				first = append(first, recorded.Version)
+				if len(first) == 3 {
+					break
+				}
+			}
+			if fmt.Sprint(first) != fmt.Sprint([]int{1, 2, 3}) {
+				t.Fatalf("versions before break = %v, want [1 2 3]", first)
+			}
+		})
+	}
+}
+
+func TestReadStreamGivenFailingStoreWhenIteratingThenYieldsTheErrorOnce(t *testing.T) {
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "catcare.db"), newTestCodec(t))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	if err := sqlite.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	var errs []error
+	for _, err := range ReadStream(context.Background(), sqlite, "cat-cmd-1", 1, 2) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil {
+		t.Fatalf("errors = %v, want exactly one", errs)
+	}
+}
+
+func TestAppendGivenMetadataWhenLoadingAndReplayingThenReturnsItWithEveryEvent(t *testing.T) {
+	ctx := context.Background()
+	metadata := Metadata{
+		Actor:         Actor{Type: ActorAI, ID: "assistant-1"},
+		RecordedAt:    time.Date(2026, time.February, 14, 12, 0, 0, 500, time.FixedZone("BRT", -3*60*60)),
+		CorrelationID: "conversation-7",
+		CausationID:   "cmd-2",
+	}
+
+	for storeName, events := range storesForTest(t) {
+		t.Run(storeName, func(t *testing.T) {
+			if _, err := events.Append(ctx, "cat-cmd-1", 0, []any{core.CatRegistered{CommandID: "cmd-1"}}, Metadata{}); err != nil {
+				t.Fatalf("append unattributed: %v", err)
+			}
+			if _, err := events.Append(ctx, "cat-cmd-1", 1, []any{core.WeightLogged{CommandID: "cmd-2"}, core.AnomalyReported{CommandID: "cmd-2"}}, metadata); err != nil {
+				t.Fatalf("append: %v", err)
+			}
+
+			records, _, err := events.Load(ctx, "cat-cmd-1")
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if len(records) != 3 || records[0].Metadata != (Metadata{}) {
+				t.Fatalf("records = %+v, want 3 with an unattributed first event", records)
+			}
+			for _, recorded := range records[1:] {
+				if !sameMetadata(recorded.Metadata, metadata) {
+					t.Fatalf("version %d metadata = %+v, want %+v", recorded.Version, recorded.Metadata, metadata)
+				}
+			}
+
+			sqlite, ok := events.(*SQLiteStore)
+			if !ok {
+				return
+			}
+			var replayed []Metadata
+			err = sqlite.Replay(ctx, func(_ context.Context, _ string, recorded RecordedEvent) error {
+				replayed = append(replayed, recorded.Metadata)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("replay: %v", err)
+			}
+			if len(replayed) != 3 || !sameMetadata(replayed[2], metadata) {
+				t.Fatalf("replayed metadata = %+v", replayed)
+			}
+		})
+	}
+}
+
+// sameMetadata compares instants rather than locations: stores keep
+// RecordedAt in UTC.
+func sameMetadata(got Metadata, want Metadata) bool {
+	return got.RecordedAt.Equal(want.RecordedAt) &&
+		got.Actor == want.Actor &&
+		got.CorrelationID == want.CorrelationID &&
+		got.CausationID == want.CausationID
+}
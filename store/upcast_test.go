@@ -0,0 +1,206 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// weighed is a test event whose payload evolved twice: v1 stored kilograms,
+// v2 switched to integer grams, and v3 added the source of the reading.
+type weighed struct {
+	EntryID string
+	Grams   int
+	Source  string
+}
+
+var weighedType = EventType{
+	Name:          "Weighed",
+	SchemaVersion: 3,
+	New:           func() any { return weighed{} },
+	Upcasters: []Upcaster{
+		func(fields map[string]json.RawMessage) error {
+			var kilograms float64
+			if err := json.Unmarshal(fields["Kilograms"], &kilograms); err != nil {
+				return err
+			}
+			grams, err := json.Marshal(int(math.Round(kilograms * 1000)))
+			if err != nil {
+				return err
+			}
+			fields["Grams"] = grams
+			delete(fields, "Kilograms")
+			return nil
+		},
+		func(fields map[string]json.RawMessage) error {
+			if _, ok := fields["Source"]; !ok {
+				fields["Source"] = json.RawMessage(`"manual"`)
+			}
+			return nil
+		},
+	},
+}
+
+func newWeighedRegistry(t *testing.T) *Registry {
+	t.Helper()
+	registry := NewRegistry()
+	if err := registry.Register(weighedType); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	return registry
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "upcast", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return payload
+}
+
+func TestRegistryGivenGoldenPayloadOfEverySchemaVersionWhenDecodeThenUpcastsToCurrentStruct(t *testing.T) {
+	registry := newWeighedRegistry(t)
+
+	cases := []struct {
+		fixture       string
+		schemaVersion int
+		want          weighed
+	}{
+		{fixture: "weighed.v1.json", schemaVersion: 1, want: weighed{EntryID: "entry-cmd-2", Grams: 4200, Source: "manual"}},
+		{fixture: "weighed.v2.json", schemaVersion: 2, want: weighed{EntryID: "entry-cmd-2", Grams: 4200, Source: "manual"}},
+		{fixture: "weighed.v3.json", schemaVersion: 3, want: weighed{EntryID: "entry-cmd-2", Grams: 4200, Source: "scale"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			decoded, err := registry.Decode("Weighed", tc.schemaVersion, readFixture(t, tc.fixture))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if decoded != any(tc.want) {
+				t.Fatalf("decoded = %+v, want %+v", decoded, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegistryGivenUnsupportedSchemaVersionWhenDecodeThenFails(t *testing.T) {
+	registry := newWeighedRegistry(t)
+
+	for _, schemaVersion := range []int{0, 4} {
+		if _, err := registry.Decode("Weighed", schemaVersion, readFixture(t, "weighed.v3.json")); err == nil {
+			t.Fatalf("expected schema version %d to be refused", schemaVersion)
+		}
+	}
+}
+
+func TestRegistryGivenMissingUpcasterWhenRegisterThenFails(t *testing.T) {
+	registry := NewRegistry()
+	incomplete := weighedType
+	incomplete.Upcasters = incomplete.Upcasters[:1]
+
+	if err := registry.Register(incomplete); err == nil {
+		t.Fatalf("expected a schema version without its upcasters to be refused")
+	}
+}
+
+func TestSQLiteStoreGivenRowsOfOldSchemaVersionsWhenLoadAndReplayThenUpcasts(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "catcare.db"), newWeighedRegistry(t))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	for version, fixture := range []string{"weighed.v1.json", "weighed.v2.json"} {
+		if _, err := store.db.ExecContext(ctx, `
+INSERT INTO events(stream_id, version, event_type, schema_version, payload) VALUES('cat-cmd-1', ?, 'Weighed', ?, ?)
+`, version+1, version+1, string(readFixture(t, fixture))); err != nil {
+			t.Fatalf("insert %s: %v", fixture, err)
+		}
+	}
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO streams(stream_id, version) VALUES('cat-cmd-1', 2)`); err != nil {
+		t.Fatalf("insert stream: %v", err)
+	}
+	if _, err := store.Append(ctx, "cat-cmd-1", 2, []any{weighed{EntryID: "entry-cmd-3", Grams: 4300, Source: "scale"}}, Metadata{}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	want := []any{
+		weighed{EntryID: "entry-cmd-2", Grams: 4200, Source: "manual"},
+		weighed{EntryID: "entry-cmd-2", Grams: 4200, Source: "manual"},
+		weighed{EntryID: "entry-cmd-3", Grams: 4300, Source: "scale"},
+	}
+	records, _, err := store.Load(ctx, "cat-cmd-1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	events := make([]any, 0, len(records))
+	for _, recorded := range records {
+		events = append(events, recorded.Event)
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Fatalf("loaded = %+v, want %+v", events, want)
+	}
+
+	var replayed []any
+	err = store.Replay(ctx, func(_ context.Context, _ string, recorded RecordedEvent) error {
+		replayed = append(replayed, recorded.Event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if fmt.Sprint(replayed) != fmt.Sprint(want) {
+		t.Fatalf("replayed = %+v, want %+v", replayed, want)
+	}
+}
+
+func TestSQLiteStoreGivenFileWithoutSchemaVersionColumnWhenOpenThenReadsRowsAsFirstVersion(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "catcare.db")
+	legacy, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open legacy: %v", err)
+	}
+	if _, err := legacy.ExecContext(ctx, `
+CREATE TABLE streams (stream_id TEXT PRIMARY KEY, version INTEGER NOT NULL);
+CREATE TABLE events (stream_id TEXT NOT NULL, version INTEGER NOT NULL, event_type TEXT NOT NULL, payload TEXT NOT NULL, PRIMARY KEY (stream_id, version));
+INSERT INTO streams(stream_id, version) VALUES('cat-cmd-1', 1);
+`); err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+	if _, err := legacy.ExecContext(ctx, `
+INSERT INTO events(stream_id, version, event_type, payload) VALUES('cat-cmd-1', 1, 'Weighed', ?)
+`, string(readFixture(t, "weighed.v1.json"))); err != nil {
+		t.Fatalf("insert legacy row: %v", err)
+	}
+	if err := legacy.Close(); err != nil {
+		t.Fatalf("close legacy: %v", err)
+	}
+
+	store, err := NewSQLiteStore(dbPath, newWeighedRegistry(t))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	records, version, err := store.Load(ctx, "cat-cmd-1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	want := weighed{EntryID: "entry-cmd-2", Grams: 4200, Source: "manual"}
+	if version != 1 || len(records) != 1 || records[0].Event != any(want) {
+		t.Fatalf("loaded %+v at version %d, want [%+v] at 1", records, version, want)
+	}
+}
@@ -0,0 +1,49 @@
+package catcare
+
+import (
+	"context"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+// upcasters holds, per event name, the chain that lifts stored payloads to
+// the current struct. Changing the shape of an event (renaming a field,
+// changing its unit) means appending an upcaster here, which also bumps the
+// schema version new rows are written with. Adding an optional field needs
+// none.
+var upcasters = map[string][]store.Upcaster{}
+
+// RegisterEvents adds every CatCare event to registry under its core name.
+func RegisterEvents(registry *store.Registry) error {
+	for _, event := range core.EventTypes() {
+		zero := event
+		name := core.EventName(zero)
+		if err := registry.Register(store.EventType{
+			Name:          name,
+			SchemaVersion: len(upcasters[name]) + 1,
+			New:           func() any { return zero },
+			Upcasters:     upcasters[name],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replayer feeds the CatCare events of a store replay to projectors, in
+// stream order. Events of other domains sharing the store are skipped.
+func Replayer(projectors ...Projector) store.ReplayFunc {
+	return func(ctx context.Context, streamID string, recorded store.RecordedEvent) error {
+		catCareEvent, ok := recorded.Event.(core.Event)
+		if !ok {
+			return nil
+		}
+		for _, projector := range projectors {
+			if err := projector.Apply(ctx, streamID, recorded.Version, catCareEvent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package catcare
+
+import (
+	"context"
+	"testing"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+func TestHandleCommandGivenEnvelopeMetadataWhenAppendThenRecordsItWithEveryEvent(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+	assistant := store.Actor{Type: store.ActorAI, ID: "assistant-1"}
+
+	if _, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterCat{CommandID: "cmd-1", Name: "Miso"},
+		Actor:       store.Actor{Type: store.ActorHuman, ID: "person-ana"},
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	result, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID:   "cat-1",
+		Command:       core.LogWeight{CommandID: "cmd-2", At: "2026-02-14T10:00:00Z", Grams: 4200},
+		Actor:         assistant,
+		CorrelationID: "conversation-7",
+		CausationID:   "proposal-3",
+	})
+	if err != nil || !result.Ok {
+		t.Fatalf("log weight: result %+v, err %v", result, err)
+	}
+
+	records, _, err := eventStore.LoadFrom(ctx, "cat-1", 1, 0)
+	if err != nil {
+		t.Fatalf("load from: %v", err)
+	}
+	want := []store.Metadata{
+		{Actor: store.Actor{Type: store.ActorHuman, ID: "person-ana"}, RecordedAt: fixedClock(), CorrelationID: "cmd-1", CausationID: "cmd-1"},
+		{Actor: assistant, RecordedAt: fixedClock(), CorrelationID: "conversation-7", CausationID: "proposal-3"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %d, want %d", len(records), len(want))
+	}
+	for index, recorded := range records {
+		if recorded.Metadata != want[index] {
+			t.Fatalf("version %d metadata = %+v, want %+v", recorded.Version, recorded.Metadata, want[index])
+		}
+	}
+}
+
+func TestHandleCommandGivenUnknownActorTypeWhenHandleThenFailsWithoutAppending(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+
+	_, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterCat{CommandID: "cmd-1", Name: "Miso"},
+		Actor:       store.Actor{Type: "robot", ID: "r2"},
+	})
+	if err == nil {
+		t.Fatalf("expected an unknown actor type to fail")
+	}
+	if _, version, _ := eventStore.Load(context.Background(), "cat-1"); version != 0 {
+		t.Fatalf("version = %d, want nothing appended", version)
+	}
+}
+
+func TestHandleCommandGivenNoActorWhenHandleThenFailsWithoutAppending(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+
+	_, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterCat{CommandID: "cmd-1", Name: "Miso"},
+		Actor:       store.Actor{ID: "anonymous"},
+	})
+	if err == nil {
+		t.Fatalf("expected a missing actor type to fail")
+	}
+	if _, version, _ := eventStore.Load(context.Background(), "cat-1"); version != 0 {
+		t.Fatalf("version = %d, want nothing appended", version)
+	}
+}
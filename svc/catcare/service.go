@@ -2,23 +2,39 @@ package catcare
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	core "github.com/wastingnotime/zeroapps/core/catcare"
 	"github.com/wastingnotime/zeroapps/store"
 )
 
+// CommandEnvelope carries a command with the context it was issued in. Actor,
+// CorrelationID and CausationID are recorded as metadata of the appended
+// events; CausationID defaults to the command ID and CorrelationID to the
+// causation.
 type CommandEnvelope struct {
 	AggregateID     string
 	Command         core.Command
 	ExpectedVersion *int
+	Actor           store.Actor
+	CorrelationID   string
+	CausationID     string
 }
 
+// Result reports the outcome of a command. On rejection, Rejection is the
+// first rejection and Rejections lists all of them (more than one only when
+// the aggregate runs with core.WithAllRejections). When the command was
+// ambiguous, Clarification is set instead and nothing was appended.
 type Result struct {
-	Ok         bool
-	NewVersion int
-	Events     []core.Event
-	Rejection  *core.Rejection
+	Ok            bool
+	NewVersion    int
+	Events        []core.Event
+	Rejection     *core.Rejection
+	Rejections    []core.Rejection
+	Clarification *core.NeedsClarification
 }
 
 type Projector interface {
@@ -26,13 +42,38 @@ type Projector interface {
 }
 
 type Service struct {
-	store      store.EventStore
-	maxRetries int
-	projectors []Projector
+	store         store.EventStore
+	maxRetries    int
+	projectors    []Projector
+	clock         func() time.Time
+	options       []core.Option
+	snapshotEvery int
 }
 
 func NewService(store store.EventStore, projectors ...Projector) *Service {
-	return &Service{store: store, maxRetries: 1, projectors: projectors}
+	return &Service{store: store, maxRetries: 1, projectors: projectors, clock: time.Now}
+}
+
+// WithAggregateOptions sets the deterministic policies (date window, weight
+// change thresholds, ...) the aggregate is loaded with for every command.
+func (s *Service) WithAggregateOptions(options ...core.Option) *Service {
+	s.options = options
+	return s
+}
+
+// WithClock replaces the clock that supplies the core's reference time.
+func (s *Service) WithClock(clock func() time.Time) *Service {
+	s.clock = clock
+	return s
+}
+
+// WithSnapshots makes the service snapshot an aggregate once every events
+// have been appended since its last snapshot, when the store is also a
+// store.SnapshotStore. Commands then restore the latest snapshot and apply
+// only the events after it. Zero or less disables snapshots.
+func (s *Service) WithSnapshots(every int) *Service {
+	s.snapshotEvery = every
+	return s
 }
 
 func (s *Service) HandleCommand(ctx context.Context, env CommandEnvelope) (Result, error) {
@@ -40,26 +81,32 @@ func (s *Service) HandleCommand(ctx context.Context, env CommandEnvelope) (Resul
 		return Result{}, fmt.Errorf("aggregate id is required")
 	}
 
-	for attempt := 0; attempt <= s.maxRetries; attempt++ {
-		rawEvents, version, err := s.store.Load(ctx, env.AggregateID)
-		if err != nil {
-			return Result{}, err
-		}
-
-		events, err := toCoreEvents(rawEvents)
-		if err != nil {
-			return Result{}, err
-		}
+	if env.Actor.Type == "" {
+		return Result{}, fmt.Errorf("actor type is required")
+	}
+	if !store.ValidActorType(env.Actor.Type) {
+		return Result{}, fmt.Errorf("unknown actor type %q", env.Actor.Type)
+	}
 
-		aggregate, err := core.LoadFrom(events)
+	now := s.clock()
+	knownAttachments, err := s.knownAttachments(ctx, core.AttachmentIDs(env.Command))
+	if err != nil {
+		return Result{}, err
+	}
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		options := append(append([]core.Option(nil), s.options...), core.WithReferenceTime(now), core.WithKnownAttachments(knownAttachments...))
+		aggregate, version, snapshotVersion, err := s.loadAggregate(ctx, env.AggregateID, options)
 		if err != nil {
 			return Result{}, err
 		}
 
 		decided, err := aggregate.Decide(env.Command)
 		if err != nil {
-			if rejection, ok := err.(core.Rejection); ok {
-				return Result{Ok: false, NewVersion: version, Rejection: &rejection}, nil
+			if rejections, ok := core.AsRejections(err); ok {
+				return rejectedResult(version, rejections), nil
+			}
+			if clarification, ok := core.AsClarification(err); ok {
+				return Result{Ok: false, NewVersion: version, Clarification: &clarification}, nil
 			}
 			return Result{}, err
 		}
@@ -69,7 +116,13 @@ func (s *Service) HandleCommand(ctx context.Context, env CommandEnvelope) (Resul
 			expected = *env.ExpectedVersion
 		}
 
-		newVersion, err := s.store.Append(ctx, env.AggregateID, expected, toAnySlice(decided))
+		// Decide has rejected a nil command by now, so the command id is safe
+		// to read.
+		metadata := eventMetadata(env, core.CommandID(env.Command), now)
+		newVersion, err := s.append(ctx, env.AggregateID, expected, decided, metadata)
+		if rejection, ok := reservationRejection(err, decided); ok {
+			return rejectedResult(version, []core.Rejection{rejection}), nil
+		}
 		if err == store.ErrConcurrencyConflict {
 			if env.ExpectedVersion != nil {
 				return Result{}, store.ErrConcurrencyConflict
@@ -85,6 +138,7 @@ func (s *Service) HandleCommand(ctx context.Context, env CommandEnvelope) (Resul
 		if err := s.publishToProjectors(ctx, env.AggregateID, newVersion, decided); err != nil {
 			return Result{}, err
 		}
+		s.snapshot(ctx, env.AggregateID, aggregate, snapshotVersion, newVersion, decided)
 
 		return Result{Ok: true, NewVersion: newVersion, Events: decided}, nil
 	}
@@ -92,6 +146,194 @@ func (s *Service) HandleCommand(ctx context.Context, env CommandEnvelope) (Resul
 	return Result{}, store.ErrConcurrencyConflict
 }
 
+// snapshotEnvelope is the serialized form of a CatCare snapshot.
+type snapshotEnvelope struct {
+	Format int
+	State  core.State
+}
+
+// loadAggregate rebuilds the aggregate from its latest usable snapshot plus
+// the events after it, which are the only ones read, or from the whole
+// stream. It also reports the stream
+// version and the version of the snapshot it started from (zero for none).
+func (s *Service) loadAggregate(ctx context.Context, streamID string, options []core.Option) (*core.CatCare, int, int, error) {
+	state, snapshotVersion, restored, err := s.loadSnapshot(ctx, streamID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if restored {
+		records, version, err := s.store.LoadFrom(ctx, streamID, snapshotVersion+1, 0)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if snapshotVersion <= version {
+			events, err := toCoreEvents(records)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			aggregate := core.Restore(state, options...)
+			for _, event := range events {
+				if err := aggregate.Apply(event); err != nil {
+					return nil, 0, 0, err
+				}
+			}
+			return aggregate, version, snapshotVersion, nil
+		}
+	}
+
+	records, version, err := s.store.Load(ctx, streamID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	events, err := toCoreEvents(records)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	aggregate, err := core.LoadFrom(events, options...)
+	return aggregate, version, 0, err
+}
+
+// loadSnapshot returns the stream's snapshot when snapshots are enabled and
+// it was taken under the current state format. Anything else is ignored and
+// the stream is replayed from the start.
+func (s *Service) loadSnapshot(ctx context.Context, streamID string) (core.State, int, bool, error) {
+	snapshots, ok := s.store.(store.SnapshotStore)
+	if !ok || s.snapshotEvery <= 0 {
+		return core.State{}, 0, false, nil
+	}
+	snapshot, ok, err := snapshots.LoadSnapshot(ctx, streamID)
+	if err != nil || !ok {
+		return core.State{}, 0, false, err
+	}
+	var envelope snapshotEnvelope
+	if err := json.Unmarshal(snapshot.State, &envelope); err != nil || envelope.Format != core.StateFormat {
+		return core.State{}, 0, false, nil
+	}
+	return envelope.State, snapshot.Version, true, nil
+}
+
+// snapshot saves the aggregate state as of newVersion once the policy's
+// number of events has accumulated since snapshotVersion. It is best effort:
+// the events are already appended, and a missing snapshot only costs replay.
+func (s *Service) snapshot(ctx context.Context, streamID string, aggregate *core.CatCare, snapshotVersion int, newVersion int, decided []core.Event) {
+	snapshots, ok := s.store.(store.SnapshotStore)
+	if !ok || s.snapshotEvery <= 0 || newVersion-snapshotVersion < s.snapshotEvery {
+		return
+	}
+	for _, event := range decided {
+		if err := aggregate.Apply(event); err != nil {
+			return
+		}
+	}
+	state, err := json.Marshal(snapshotEnvelope{Format: core.StateFormat, State: aggregate.State()})
+	if err != nil {
+		return
+	}
+	_ = snapshots.SaveSnapshot(ctx, streamID, store.Snapshot{Version: newVersion, State: state})
+}
+
+// eventMetadata builds the metadata of the events a command appends,
+// recorded at the service clock's time.
+func eventMetadata(env CommandEnvelope, commandID string, recordedAt time.Time) store.Metadata {
+	causationID := env.CausationID
+	if causationID == "" {
+		causationID = commandID
+	}
+	correlationID := env.CorrelationID
+	if correlationID == "" {
+		correlationID = causationID
+	}
+	return store.Metadata{
+		Actor:         env.Actor,
+		RecordedAt:    recordedAt,
+		CorrelationID: correlationID,
+		CausationID:   causationID,
+	}
+}
+
+func rejectedResult(version int, rejections []core.Rejection) Result {
+	first := rejections[0]
+	return Result{Ok: false, NewVersion: version, Rejection: &first, Rejections: rejections}
+}
+
+// knownAttachments returns the attachment IDs among ids that the attachment
+// registry has minted, i.e. whose stream starts with AttachmentRegistered.
+func (s *Service) knownAttachments(ctx context.Context, ids []string) ([]string, error) {
+	var known []string
+	for _, id := range ids {
+		records, version, err := s.store.Load(ctx, core.AttachmentStreamID(id))
+		if err != nil {
+			return nil, err
+		}
+		if version == 0 {
+			continue
+		}
+		if registered, ok := records[0].Event.(core.AttachmentRegistered); ok && registered.AttachmentID == id {
+			known = append(known, id)
+		}
+	}
+	return known, nil
+}
+
+// reservation is a value that must be unique across cats, claimed together
+// with the events that carry it.
+type reservation struct {
+	scope    string
+	value    string
+	field    string
+	conflict string
+}
+
+const reservationScopeMicrochip = "microchip"
+
+func reservationsFor(events []core.Event) []reservation {
+	var reservations []reservation
+	for _, event := range events {
+		switch ev := event.(type) {
+		case core.MicrochipRegistered:
+			reservations = append(reservations, reservation{
+				scope:    reservationScopeMicrochip,
+				value:    ev.ChipNumber,
+				field:    "chip_number",
+				conflict: core.CodeMicrochipInUse,
+			})
+		}
+	}
+	return reservations
+}
+
+// append stores events, claiming the unique values they carry in the same
+// transaction when the store supports reservations.
+func (s *Service) append(ctx context.Context, streamID string, expectedVersion int, events []core.Event, metadata store.Metadata) (int, error) {
+	reservations := reservationsFor(events)
+	reserver, ok := s.store.(store.ReservationStore)
+	if !ok || len(reservations) == 0 {
+		return s.store.Append(ctx, streamID, expectedVersion, toAnySlice(events), metadata)
+	}
+
+	claim := make([]store.Reservation, 0, len(reservations))
+	for _, item := range reservations {
+		claim = append(claim, store.Reservation{Scope: item.scope, Value: item.value})
+	}
+	return reserver.AppendReserving(ctx, streamID, expectedVersion, toAnySlice(events), metadata, claim, nil)
+}
+
+// reservationRejection turns a value owned by another stream into the
+// rejection of the reservation that claimed it.
+func reservationRejection(err error, events []core.Event) (core.Rejection, bool) {
+	var reserved *store.ReservedError
+	if !errors.As(err, &reserved) {
+		return core.Rejection{}, false
+	}
+	for _, item := range reservationsFor(events) {
+		if item.scope == reserved.Reservation.Scope && item.value == reserved.Reservation.Value {
+			return core.Rejection{Code: item.conflict, Message: "already registered to another cat", Field: item.field}, true
+		}
+	}
+	return core.Rejection{}, false
+}
+
 func (s *Service) publishToProjectors(ctx context.Context, streamID string, newVersion int, events []core.Event) error {
 	if len(s.projectors) == 0 || len(events) == 0 {
 		return nil
@@ -109,16 +351,16 @@ func (s *Service) publishToProjectors(ctx context.Context, streamID string, newV
 	return nil
 }
 
-func toCoreEvents(events []any) ([]core.Event, error) {
-	if len(events) == 0 {
+func toCoreEvents(records []store.RecordedEvent) ([]core.Event, error) {
+	if len(records) == 0 {
 		return nil, nil
 	}
 
-	typed := make([]core.Event, 0, len(events))
-	for _, event := range events {
-		typedEvent, ok := event.(core.Event)
+	typed := make([]core.Event, 0, len(records))
+	for _, recorded := range records {
+		typedEvent, ok := recorded.Event.(core.Event)
 		if !ok {
-			return nil, fmt.Errorf("unexpected event type %T", event)
+			return nil, fmt.Errorf("unexpected event type %T", recorded.Event)
 		}
 		typed = append(typed, typedEvent)
 	}
@@ -3,6 +3,7 @@ package catcare
 import (
 	"context"
 	"testing"
+	"time"
 
 	core "github.com/wastingnotime/zeroapps/core/catcare"
 	"github.com/wastingnotime/zeroapps/store"
@@ -27,10 +28,16 @@ func (p *spyProjector) Apply(_ context.Context, streamID string, version int, ev
 	return nil
 }
 
+var testActor = store.Actor{Type: store.ActorHuman, ID: "tester"}
+
+func fixedClock() time.Time {
+	return time.Date(2026, time.February, 14, 12, 0, 0, 0, time.UTC)
+}
+
 func TestHandleCommandHappyPath(t *testing.T) {
 	eventStore := store.NewInMemoryStore()
 	projection := &spyProjector{}
-	service := NewService(eventStore, projection)
+	service := NewService(eventStore, projection).WithClock(fixedClock)
 
 	result, err := service.HandleCommand(context.Background(), CommandEnvelope{
 		AggregateID: "cat-1",
@@ -39,6 +46,7 @@ func TestHandleCommandHappyPath(t *testing.T) {
 			Name:      "Miso",
 			BirthDate: "2023-01-01",
 		},
+		Actor: testActor,
 	})
 	if err != nil {
 		t.Fatalf("handle command: %v", err)
@@ -65,7 +73,7 @@ func TestHandleCommandHappyPath(t *testing.T) {
 
 func TestHandleCommandExpectedVersionConflict(t *testing.T) {
 	eventStore := store.NewInMemoryStore()
-	service := NewService(eventStore)
+	service := NewService(eventStore).WithClock(fixedClock)
 
 	_, err := service.HandleCommand(context.Background(), CommandEnvelope{
 		AggregateID: "cat-1",
@@ -73,6 +81,7 @@ func TestHandleCommandExpectedVersionConflict(t *testing.T) {
 			CommandID: "cmd-1",
 			Name:      "Miso",
 		},
+		Actor: testActor,
 	})
 	if err != nil {
 		t.Fatalf("seed register: %v", err)
@@ -87,8 +96,314 @@ func TestHandleCommandExpectedVersionConflict(t *testing.T) {
 			At:        "2026-02-14T10:00:00Z",
 			Grams:     4200,
 		},
+		Actor: testActor,
 	})
 	if err != store.ErrConcurrencyConflict {
 		t.Fatalf("expected conflict, got %v", err)
 	}
 }
+
+func TestHandleCommandGivenFutureTimestampWhenLogWeightThenRejectsAgainstClock(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+
+	_, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "cat-1",
+		Command: core.RegisterCat{
+			CommandID: "cmd-1",
+			Name:      "Miso",
+		},
+		Actor: testActor,
+	})
+	if err != nil {
+		t.Fatalf("seed register: %v", err)
+	}
+
+	result, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "cat-1",
+		Command: core.LogWeight{
+			CommandID: "cmd-2",
+			At:        "2026-02-16T10:00:00Z",
+			Grams:     4200,
+		},
+		Actor: testActor,
+	})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if result.Ok || result.Rejection == nil {
+		t.Fatalf("expected rejection, got %+v", result)
+	}
+	if result.Rejection.Code != core.CodeImplausibleDate {
+		t.Fatalf("expected %q, got %q", core.CodeImplausibleDate, result.Rejection.Code)
+	}
+}
+
+func TestHandleCommandGivenTimestampWithoutOffsetWhenLogWeightThenAsksForClarification(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+
+	_, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "cat-1",
+		Command: core.RegisterCat{
+			CommandID: "cmd-1",
+			Name:      "Miso",
+		},
+		Actor: testActor,
+	})
+	if err != nil {
+		t.Fatalf("seed register: %v", err)
+	}
+
+	result, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "cat-1",
+		Command: core.LogWeight{
+			CommandID: "cmd-2",
+			At:        "2026-02-14T08:00",
+			Grams:     4200,
+		},
+		Actor: testActor,
+	})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if result.Ok || result.Clarification == nil {
+		t.Fatalf("expected clarification, got %+v", result)
+	}
+	if result.Rejection != nil || len(result.Rejections) != 0 {
+		t.Fatalf("clarification must not be reported as a rejection, got %+v", result.Rejections)
+	}
+	if result.Clarification.Field != "at" || result.NewVersion != 1 {
+		t.Fatalf("unexpected clarification %+v at version %d", result.Clarification, result.NewVersion)
+	}
+
+	_, version, err := eventStore.Load(context.Background(), "cat-1")
+	if err != nil {
+		t.Fatalf("load stream: %v", err)
+	}
+	if version != 1 {
+		t.Fatalf("expected nothing appended, got version %d", version)
+	}
+}
+
+func TestHandleCommandGivenChipOnAnotherCatWhenRegisterMicrochipThenRejectsWithMicrochipInUse(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+
+	for _, catID := range []string{"cat-1", "cat-2"} {
+		result, err := service.HandleCommand(ctx, CommandEnvelope{
+			AggregateID: catID,
+			Command:     core.RegisterCat{CommandID: "register-" + catID, Name: "Miso"},
+			Actor:       testActor,
+		})
+		if err != nil || !result.Ok {
+			t.Fatalf("register %s: %v %v", catID, err, result.Rejection)
+		}
+	}
+
+	first, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterMicrochip{CommandID: "chip-1", ChipNumber: "985 112 003 456 789"},
+		Actor:       testActor,
+	})
+	if err != nil || !first.Ok {
+		t.Fatalf("register microchip on cat-1: %v %v", err, first.Rejection)
+	}
+
+	second, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-2",
+		Command:     core.RegisterMicrochip{CommandID: "chip-2", ChipNumber: "985112003456789"},
+		Actor:       testActor,
+	})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if second.Ok {
+		t.Fatal("expected rejection")
+	}
+	if second.Rejection.Code != core.CodeMicrochipInUse {
+		t.Fatalf("expected %q, got %q", core.CodeMicrochipInUse, second.Rejection.Code)
+	}
+
+	records, version, err := eventStore.Load(ctx, "cat-2")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if version != 1 || len(records) != 1 {
+		t.Fatalf("expected no events appended to cat-2, got version %d", version)
+	}
+}
+
+func TestHandleCommandGivenAllRejectionsModeWhenSeveralFieldsInvalidThenListsEveryRejection(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(store.NewInMemoryStore()).
+		WithClock(fixedClock).
+		WithAggregateOptions(core.WithAllRejections())
+
+	registered, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterCat{CommandID: "cmd-1", Name: "Miso"},
+		Actor:       testActor,
+	})
+	if err != nil || !registered.Ok {
+		t.Fatalf("register: %v %v", err, registered.Rejection)
+	}
+
+	result, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.LogWeight{CommandID: "cmd-2", At: "", Grams: 0},
+		Actor:       testActor,
+	})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if result.Ok {
+		t.Fatal("expected rejection")
+	}
+	if len(result.Rejections) != 2 {
+		t.Fatalf("expected 2 rejections, got %+v", result.Rejections)
+	}
+	if result.Rejections[0].Field != "at" || result.Rejections[1].Field != "grams" {
+		t.Fatalf("unexpected rejections %+v", result.Rejections)
+	}
+	if result.Rejection == nil || *result.Rejection != result.Rejections[0] {
+		t.Fatalf("expected Rejection to be the first rejection, got %v", result.Rejection)
+	}
+}
+
+func TestHandleCommandGivenRegisteredAttachmentWhenReportAnomalyThenAcceptsOnlyMintedIDs(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock)
+
+	registered, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterCat{CommandID: "cmd-1", Name: "Miso"},
+		Actor:       testActor,
+	})
+	if err != nil || !registered.Ok {
+		t.Fatalf("register: %v %v", err, registered.Rejection)
+	}
+	attachment := core.AttachmentRegistered{
+		CommandID:    "attachment-1",
+		AttachmentID: "attachment-1",
+		ContentHash:  "sha256:00",
+		MIMEType:     "image/jpeg",
+		SizeBytes:    1,
+	}
+	if _, err := eventStore.Append(ctx, core.AttachmentStreamID("attachment-1"), 0, []any{attachment}, store.Metadata{}); err != nil {
+		t.Fatalf("register attachment: %v", err)
+	}
+
+	accepted, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command: core.ReportAnomaly{
+			CommandID:     "cmd-2",
+			At:            "2026-02-14T08:00:00Z",
+			Summary:       "Skin lesion",
+			Severity:      core.SeverityLow,
+			AttachmentIDs: []string{" attachment-1 ", "attachment-1"},
+		},
+		Actor: testActor,
+	})
+	if err != nil || !accepted.Ok {
+		t.Fatalf("report with padded minted attachment: %v %v", err, accepted.Rejection)
+	}
+
+	rejected, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command: core.ReportAnomaly{
+			CommandID:     "cmd-3",
+			At:            "2026-02-14T08:00:00Z",
+			Summary:       "Skin lesion",
+			Severity:      core.SeverityLow,
+			AttachmentIDs: []string{"attachment-2"},
+		},
+		Actor: testActor,
+	})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if rejected.Ok || rejected.Rejection.Code != core.CodeUnknownAttachment {
+		t.Fatalf("expected %q, got %+v", core.CodeUnknownAttachment, rejected)
+	}
+}
+
+// interleavingStore runs a competing command just before the first
+// reserving append, as if both commands had loaded the stream at once.
+type interleavingStore struct {
+	*store.InMemoryStore
+	competitor func()
+}
+
+func (s *interleavingStore) AppendReserving(ctx context.Context, streamID string, expectedVersion int, events []any, metadata store.Metadata, claim []store.Reservation, release []store.Reservation) (int, error) {
+	if competitor := s.competitor; competitor != nil {
+		s.competitor = nil
+		competitor()
+	}
+	return s.InMemoryStore.AppendReserving(ctx, streamID, expectedVersion, events, metadata, claim, release)
+}
+
+func TestHandleCommandGivenConcurrentChipRegistrationsOnOneCatWhenOneConflictsThenChipStaysReserved(t *testing.T) {
+	ctx := context.Background()
+	eventStore := &interleavingStore{InMemoryStore: store.NewInMemoryStore()}
+	service := NewService(eventStore).WithClock(fixedClock)
+	for _, catID := range []string{"cat-1", "cat-2"} {
+		if result, err := service.HandleCommand(ctx, CommandEnvelope{
+			AggregateID: catID,
+			Command:     core.RegisterCat{CommandID: "register-" + catID, Name: "Miso"},
+			Actor:       testActor,
+		}); err != nil || !result.Ok {
+			t.Fatalf("register %s: %v %v", catID, err, result.Rejection)
+		}
+	}
+
+	var winner Result
+	eventStore.competitor = func() {
+		var err error
+		winner, err = service.HandleCommand(ctx, CommandEnvelope{
+			AggregateID: "cat-1",
+			Command:     core.RegisterMicrochip{CommandID: "chip-winner", ChipNumber: "985112003456789"},
+			Actor:       testActor,
+		})
+		if err != nil {
+			t.Errorf("winner: %v", err)
+		}
+	}
+	loser, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-1",
+		Command:     core.RegisterMicrochip{CommandID: "chip-loser", ChipNumber: "985112003456789"},
+		Actor:       testActor,
+	})
+	if err != nil {
+		t.Fatalf("loser: %v", err)
+	}
+	if !winner.Ok || loser.Rejection == nil || loser.Rejection.Code != core.CodeDuplicateMicrochip {
+		t.Fatalf("expected the winner to register and the retried loser to be a duplicate, got %+v and %+v", winner, loser)
+	}
+
+	other, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID: "cat-2",
+		Command:     core.RegisterMicrochip{CommandID: "chip-other", ChipNumber: "985112003456789"},
+		Actor:       testActor,
+	})
+	if err != nil {
+		t.Fatalf("cat-2: %v", err)
+	}
+	if other.Rejection == nil || other.Rejection.Code != core.CodeMicrochipInUse {
+		t.Fatalf("expected %q for cat-2, got %+v", core.CodeMicrochipInUse, other)
+	}
+}
+
+func TestHandleCommandGivenNilCommandWhenHandleThenRejectsInvalidCommand(t *testing.T) {
+	service := NewService(store.NewInMemoryStore()).WithClock(fixedClock)
+
+	result, err := service.HandleCommand(context.Background(), CommandEnvelope{AggregateID: "cat-1", Actor: testActor})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if result.Ok || result.Rejection == nil || result.Rejection.Code != core.CodeInvalidCommand {
+		t.Fatalf("expected %q rejection, got %+v", core.CodeInvalidCommand, result)
+	}
+}
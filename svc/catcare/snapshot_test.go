@@ -0,0 +1,148 @@
+package catcare
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	core "github.com/wastingnotime/zeroapps/core/catcare"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+func TestHandleCommandGivenSnapshotPolicyWhenEventsAccumulateThenSnapshotsEveryNEvents(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock).WithSnapshots(2)
+
+	commands := []core.Command{
+		core.RegisterCat{CommandID: "cmd-1", Name: "Miso", BirthDate: "2023-01-01"},
+		core.LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4200},
+		core.LogWeight{CommandID: "cmd-3", At: "2026-02-11T08:00:00Z", Grams: 4210},
+	}
+	wantSnapshotVersions := []int{0, 2, 2}
+	for index, command := range commands {
+		result, err := service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: command, Actor: testActor})
+		if err != nil || !result.Ok {
+			t.Fatalf("handle %T: result %+v, err %v", command, result, err)
+		}
+		snapshot, _, err := eventStore.LoadSnapshot(ctx, "cat-1")
+		if err != nil {
+			t.Fatalf("load snapshot: %v", err)
+		}
+		if snapshot.Version != wantSnapshotVersions[index] {
+			t.Fatalf("after %T snapshot version = %d, want %d", command, snapshot.Version, wantSnapshotVersions[index])
+		}
+	}
+
+	snapshot, _, _ := eventStore.LoadSnapshot(ctx, "cat-1")
+	var envelope snapshotEnvelope
+	if err := json.Unmarshal(snapshot.State, &envelope); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+	if envelope.Format != core.StateFormat || len(envelope.State.WeightEntries) != 1 || len(envelope.State.ProcessedCommandIDs) != 2 {
+		t.Fatalf("snapshot = %+v, want the state after cmd-2", envelope)
+	}
+}
+
+func TestHandleCommandGivenSnapshotWhenHandleThenRestoresItAndAppliesOnlyLaterEvents(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock).WithSnapshots(100)
+	for _, command := range []core.Command{
+		core.RegisterCat{CommandID: "cmd-1", Name: "Miso", BirthDate: "2023-01-01"},
+		core.RenameCat{CommandID: "cmd-2", NewName: "Mochi"},
+	} {
+		if _, err := service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: command, Actor: testActor}); err != nil {
+			t.Fatalf("seed %T: %v", command, err)
+		}
+	}
+
+	// The snapshot at version 1 remembers a command the events do not, so
+	// a duplicate rejection proves it was used; the rename after it must
+	// still be applied on top.
+	state := core.State{CatID: "cat-1", Name: "Miso", Registered: true, Status: core.LifecycleActive, ProcessedCommandIDs: []string{"cmd-1", "cmd-from-snapshot"}}
+	saveSnapshot(t, eventStore, core.StateFormat, 1, state)
+
+	result, err := service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: core.LogWeight{CommandID: "cmd-from-snapshot", At: "2026-02-10T08:00:00Z", Grams: 4200}, Actor: testActor})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if result.Ok || result.Rejection.Code != core.CodeDuplicateCommand {
+		t.Fatalf("expected duplicate_command from the snapshot, got %+v", result)
+	}
+	result, err = service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: core.RenameCat{CommandID: "cmd-3", NewName: "Mochi"}, Actor: testActor})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if result.Ok || result.Rejection.Code != core.CodeUnchangedName {
+		t.Fatalf("expected unchanged_name from the event after the snapshot, got %+v", result)
+	}
+}
+
+func TestHandleCommandGivenSnapshotOfAnotherFormatWhenHandleThenReplaysTheStream(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore).WithClock(fixedClock).WithSnapshots(100)
+	if _, err := service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: core.RegisterCat{CommandID: "cmd-1", Name: "Miso"}, Actor: testActor}); err != nil {
+		t.Fatalf("seed register: %v", err)
+	}
+	saveSnapshot(t, eventStore, core.StateFormat+1, 1, core.State{ProcessedCommandIDs: []string{"cmd-2"}})
+
+	result, err := service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: core.LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4200}, Actor: testActor})
+	if err != nil {
+		t.Fatalf("handle command: %v", err)
+	}
+	if !result.Ok {
+		t.Fatalf("expected the stale snapshot to be ignored, got rejection %v", result.Rejection)
+	}
+}
+
+func saveSnapshot(t *testing.T, snapshots store.SnapshotStore, format int, version int, state core.State) {
+	t.Helper()
+	payload, err := json.Marshal(snapshotEnvelope{Format: format, State: state})
+	if err != nil {
+		t.Fatalf("encode snapshot: %v", err)
+	}
+	if err := snapshots.SaveSnapshot(context.Background(), "cat-1", store.Snapshot{Version: version, State: payload}); err != nil {
+		t.Fatalf("save snapshot: %v", err)
+	}
+}
+
+// tailRecordingStore records how the service reads streams.
+type tailRecordingStore struct {
+	*store.InMemoryStore
+	fullLoads int
+	fromLoads []int
+}
+
+func (s *tailRecordingStore) Load(ctx context.Context, streamID string) ([]store.RecordedEvent, int, error) {
+	s.fullLoads++
+	return s.InMemoryStore.Load(ctx, streamID)
+}
+
+func (s *tailRecordingStore) LoadFrom(ctx context.Context, streamID string, fromVersion int, limit int) ([]store.RecordedEvent, int, error) {
+	s.fromLoads = append(s.fromLoads, fromVersion)
+	return s.InMemoryStore.LoadFrom(ctx, streamID, fromVersion, limit)
+}
+
+func TestHandleCommandGivenSnapshotWhenHandleThenReadsOnlyTheEventsAfterIt(t *testing.T) {
+	ctx := context.Background()
+	eventStore := &tailRecordingStore{InMemoryStore: store.NewInMemoryStore()}
+	service := NewService(eventStore).WithClock(fixedClock).WithSnapshots(2)
+	for _, command := range []core.Command{
+		core.RegisterCat{CommandID: "cmd-1", Name: "Miso"},
+		core.LogWeight{CommandID: "cmd-2", At: "2026-02-10T08:00:00Z", Grams: 4200},
+		core.LogWeight{CommandID: "cmd-3", At: "2026-02-11T08:00:00Z", Grams: 4210},
+	} {
+		if _, err := service.HandleCommand(ctx, CommandEnvelope{AggregateID: "cat-1", Command: command, Actor: testActor}); err != nil {
+			t.Fatalf("handle %T: %v", command, err)
+		}
+	}
+
+	if eventStore.fullLoads != 2 {
+		t.Fatalf("full loads = %d, want 2 (before the first snapshot)", eventStore.fullLoads)
+	}
+	if len(eventStore.fromLoads) != 1 || eventStore.fromLoads[0] != 3 {
+		t.Fatalf("tail loads from = %v, want [3]", eventStore.fromLoads)
+	}
+}
@@ -0,0 +1,21 @@
+package household
+
+import (
+	core "github.com/wastingnotime/zeroapps/core/household"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+// RegisterEvents adds every Household event to registry under its core name.
+func RegisterEvents(registry *store.Registry) error {
+	for _, event := range core.EventTypes() {
+		zero := event
+		if err := registry.Register(store.EventType{
+			Name:          core.EventName(zero),
+			SchemaVersion: 1,
+			New:           func() any { return zero },
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
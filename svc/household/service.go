@@ -0,0 +1,225 @@
+package household
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	catcare "github.com/wastingnotime/zeroapps/core/catcare"
+	core "github.com/wastingnotime/zeroapps/core/household"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+// CommandEnvelope carries a command with the context it was issued in, as in
+// the CatCare service: Actor, CorrelationID and CausationID become metadata
+// of the appended events.
+type CommandEnvelope struct {
+	AggregateID     string
+	Command         core.Command
+	ExpectedVersion *int
+	Actor           store.Actor
+	CorrelationID   string
+	CausationID     string
+}
+
+type Result struct {
+	Ok         bool
+	NewVersion int
+	Events     []core.Event
+	Rejection  *core.Rejection
+}
+
+// Service handles Household commands. A cat belongs to at most one
+// household: when the store supports reservations, the service claims the
+// cat for the household and releases it when the cat is removed, in the same
+// transaction as the events.
+type Service struct {
+	store      store.EventStore
+	maxRetries int
+	clock      func() time.Time
+}
+
+func NewService(store store.EventStore) *Service {
+	return &Service{store: store, maxRetries: 1, clock: time.Now}
+}
+
+// WithClock replaces the clock that supplies the recorded time of events.
+func (s *Service) WithClock(clock func() time.Time) *Service {
+	s.clock = clock
+	return s
+}
+
+const reservationScopeCat = "household_cat"
+
+func (s *Service) HandleCommand(ctx context.Context, env CommandEnvelope) (Result, error) {
+	if env.AggregateID == "" {
+		return Result{}, fmt.Errorf("aggregate id is required")
+	}
+	if env.Actor.Type == "" {
+		return Result{}, fmt.Errorf("actor type is required")
+	}
+	if !store.ValidActorType(env.Actor.Type) {
+		return Result{}, fmt.Errorf("unknown actor type %q", env.Actor.Type)
+	}
+	now := s.clock()
+
+	var options []core.Option
+	if add, ok := env.Command.(core.AddCatToHousehold); ok {
+		catID := strings.TrimSpace(add.CatID)
+		active, err := s.activeCat(ctx, catID)
+		if err != nil {
+			return Result{}, err
+		}
+		if active {
+			options = append(options, core.WithKnownCats(catID))
+		}
+	}
+
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		records, version, err := s.store.Load(ctx, env.AggregateID)
+		if err != nil {
+			return Result{}, err
+		}
+
+		events, err := toCoreEvents(records)
+		if err != nil {
+			return Result{}, err
+		}
+
+		aggregate, err := core.LoadFrom(events, options...)
+		if err != nil {
+			return Result{}, err
+		}
+
+		decided, err := aggregate.Decide(env.Command)
+		if err != nil {
+			if rejection, ok := err.(core.Rejection); ok {
+				return Result{Ok: false, NewVersion: version, Rejection: &rejection}, nil
+			}
+			return Result{}, err
+		}
+
+		expected := version
+		if env.ExpectedVersion != nil {
+			expected = *env.ExpectedVersion
+		}
+
+		// Decide has rejected a nil command by now, so the command id is safe
+		// to read.
+		metadata := eventMetadata(env, core.CommandID(env.Command), now)
+		newVersion, err := s.append(ctx, env.AggregateID, expected, decided, metadata)
+		if errors.Is(err, store.ErrAlreadyReserved) {
+			rejection := core.Rejection{Code: core.CodeCatInAnotherHousehold, Message: "cat belongs to another household", Field: "cat_id"}
+			return Result{Ok: false, NewVersion: version, Rejection: &rejection}, nil
+		}
+		if err == store.ErrConcurrencyConflict {
+			if env.ExpectedVersion != nil {
+				return Result{}, store.ErrConcurrencyConflict
+			}
+			if attempt < s.maxRetries {
+				continue
+			}
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		return Result{Ok: true, NewVersion: newVersion, Events: decided}, nil
+	}
+
+	return Result{}, store.ErrConcurrencyConflict
+}
+
+// activeCat reports whether catID names a registered CatCare stream whose cat
+// is neither deceased nor archived.
+func (s *Service) activeCat(ctx context.Context, catID string) (bool, error) {
+	if catID == "" {
+		return false, nil
+	}
+	records, version, err := s.store.Load(ctx, catID)
+	if err != nil || version == 0 {
+		return false, err
+	}
+	events := make([]catcare.Event, 0, len(records))
+	for _, recorded := range records {
+		event, ok := recorded.Event.(catcare.Event)
+		if !ok {
+			return false, nil
+		}
+		events = append(events, event)
+	}
+	cat, err := catcare.LoadFrom(events)
+	if err != nil {
+		return false, err
+	}
+	return cat.Registered && cat.Status == catcare.LifecycleActive, nil
+}
+
+// append stores events and, when the store supports reservations, claims
+// every cat they add and releases every cat they remove in the same
+// transaction.
+func (s *Service) append(ctx context.Context, streamID string, expectedVersion int, events []core.Event, metadata store.Metadata) (int, error) {
+	reserver, ok := s.store.(store.ReservationStore)
+	if !ok {
+		return s.store.Append(ctx, streamID, expectedVersion, toAnySlice(events), metadata)
+	}
+
+	var claim, release []store.Reservation
+	for _, event := range events {
+		switch ev := event.(type) {
+		case core.CatAddedToHousehold:
+			claim = append(claim, store.Reservation{Scope: reservationScopeCat, Value: ev.CatID})
+		case core.CatRemovedFromHousehold:
+			release = append(release, store.Reservation{Scope: reservationScopeCat, Value: ev.CatID})
+		}
+	}
+	return reserver.AppendReserving(ctx, streamID, expectedVersion, toAnySlice(events), metadata, claim, release)
+}
+
+func toCoreEvents(records []store.RecordedEvent) ([]core.Event, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	typed := make([]core.Event, 0, len(records))
+	for _, recorded := range records {
+		typedEvent, ok := recorded.Event.(core.Event)
+		if !ok {
+			return nil, fmt.Errorf("unexpected event type %T", recorded.Event)
+		}
+		typed = append(typed, typedEvent)
+	}
+	return typed, nil
+}
+
+func toAnySlice(events []core.Event) []any {
+	if len(events) == 0 {
+		return nil
+	}
+	raw := make([]any, 0, len(events))
+	for _, event := range events {
+		raw = append(raw, event)
+	}
+	return raw
+}
+
+// eventMetadata builds the metadata of the events a command appends:
+// CausationID defaults to the command ID and CorrelationID to the causation.
+func eventMetadata(env CommandEnvelope, commandID string, recordedAt time.Time) store.Metadata {
+	causationID := env.CausationID
+	if causationID == "" {
+		causationID = commandID
+	}
+	correlationID := env.CorrelationID
+	if correlationID == "" {
+		correlationID = causationID
+	}
+	return store.Metadata{
+		Actor:         env.Actor,
+		RecordedAt:    recordedAt,
+		CorrelationID: correlationID,
+		CausationID:   causationID,
+	}
+}
@@ -0,0 +1,186 @@
+package household
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	catcare "github.com/wastingnotime/zeroapps/core/catcare"
+	core "github.com/wastingnotime/zeroapps/core/household"
+	"github.com/wastingnotime/zeroapps/store"
+)
+
+var testActor = store.Actor{Type: store.ActorHuman, ID: "tester"}
+
+func seedCat(t *testing.T, eventStore store.EventStore, catID string, events ...any) {
+	t.Helper()
+	registered := catcare.CatRegistered{CommandID: "register-" + catID, CatID: catID, Name: "Miso"}
+	if _, err := eventStore.Append(context.Background(), catID, 0, append([]any{registered}, events...), store.Metadata{}); err != nil {
+		t.Fatalf("seed %s: %v", catID, err)
+	}
+}
+
+func handle(t *testing.T, service *Service, householdID string, command core.Command) Result {
+	t.Helper()
+	result, err := service.HandleCommand(context.Background(), CommandEnvelope{AggregateID: householdID, Command: command, Actor: testActor})
+	if err != nil {
+		t.Fatalf("handle %T: %v", command, err)
+	}
+	return result
+}
+
+func TestHandleCommandGivenCatInAnotherHouseholdWhenAddThenRejectsUntilRemoved(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore)
+	seedCat(t, eventStore, "cat-1")
+
+	for _, householdID := range []string{"household-a", "household-b"} {
+		created := handle(t, service, householdID, core.CreateHousehold{CommandID: "create-" + householdID, Name: "Home", OwnerRef: "person-ana"})
+		if !created.Ok {
+			t.Fatalf("create %s: %v", householdID, created.Rejection)
+		}
+	}
+
+	if added := handle(t, service, "household-a", core.AddCatToHousehold{CommandID: "add-a", CatID: "cat-1"}); !added.Ok {
+		t.Fatalf("add to household-a: %v", added.Rejection)
+	}
+	rejected := handle(t, service, "household-b", core.AddCatToHousehold{CommandID: "add-b", CatID: "cat-1"})
+	if rejected.Ok || rejected.Rejection.Code != core.CodeCatInAnotherHousehold {
+		t.Fatalf("expected %q, got %+v", core.CodeCatInAnotherHousehold, rejected)
+	}
+	_, version, err := eventStore.Load(context.Background(), "household-b")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if version != 1 {
+		t.Fatalf("expected nothing appended to household-b, got version %d", version)
+	}
+
+	if removed := handle(t, service, "household-a", core.RemoveCatFromHousehold{CommandID: "remove-a", CatID: "cat-1"}); !removed.Ok {
+		t.Fatalf("remove from household-a: %v", removed.Rejection)
+	}
+	if moved := handle(t, service, "household-b", core.AddCatToHousehold{CommandID: "add-b-again", CatID: "cat-1"}); !moved.Ok {
+		t.Fatalf("add to household-b after removal: %v", moved.Rejection)
+	}
+}
+
+func TestHandleCommandGivenCatNotActiveWhenAddThenRejectsUnknownCat(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore)
+	seedCat(t, eventStore, "cat-archived", catcare.CatArchived{CommandID: "archive", CatID: "cat-archived"})
+
+	if created := handle(t, service, "household-a", core.CreateHousehold{CommandID: "create", Name: "Home", OwnerRef: "person-ana"}); !created.Ok {
+		t.Fatalf("create: %v", created.Rejection)
+	}
+
+	for _, catID := range []string{"cat-archived", "cat-never-registered"} {
+		result := handle(t, service, "household-a", core.AddCatToHousehold{CommandID: "add-" + catID, CatID: catID})
+		if result.Ok || result.Rejection.Code != core.CodeUnknownCat {
+			t.Fatalf("%s: expected %q, got %+v", catID, core.CodeUnknownCat, result)
+		}
+	}
+}
+
+func TestHandleCommandGivenActorWhenAppendThenRecordsMetadataFromServiceClock(t *testing.T) {
+	ctx := context.Background()
+	eventStore := store.NewInMemoryStore()
+	recordedAt := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	service := NewService(eventStore).WithClock(func() time.Time { return recordedAt })
+
+	result, err := service.HandleCommand(ctx, CommandEnvelope{
+		AggregateID:   "household-a",
+		Command:       core.CreateHousehold{CommandID: "create", Name: "Home", OwnerRef: "person-ana"},
+		Actor:         store.Actor{Type: store.ActorSystem, ID: "importer"},
+		CorrelationID: "import-42",
+	})
+	if err != nil || !result.Ok {
+		t.Fatalf("create: result %+v, err %v", result, err)
+	}
+
+	records, _, err := eventStore.LoadFrom(ctx, "household-a", 1, 0)
+	if err != nil {
+		t.Fatalf("load from: %v", err)
+	}
+	want := store.Metadata{Actor: store.Actor{Type: store.ActorSystem, ID: "importer"}, RecordedAt: recordedAt, CorrelationID: "import-42", CausationID: "create"}
+	if len(records) != 1 || records[0].Metadata != want {
+		t.Fatalf("records = %+v, want one with metadata %+v", records, want)
+	}
+}
+
+// interleavingStore runs a competing command just before the first
+// reserving append, as if both commands had loaded the stream at once.
+type interleavingStore struct {
+	*store.InMemoryStore
+	competitor func()
+}
+
+func (s *interleavingStore) AppendReserving(ctx context.Context, streamID string, expectedVersion int, events []any, metadata store.Metadata, claim []store.Reservation, release []store.Reservation) (int, error) {
+	if competitor := s.competitor; competitor != nil && len(claim) > 0 {
+		s.competitor = nil
+		competitor()
+	}
+	return s.InMemoryStore.AppendReserving(ctx, streamID, expectedVersion, events, metadata, claim, release)
+}
+
+func TestHandleCommandGivenConcurrentAddsOfOneCatWhenOneConflictsThenCatStaysClaimed(t *testing.T) {
+	eventStore := &interleavingStore{InMemoryStore: store.NewInMemoryStore()}
+	service := NewService(eventStore)
+	seedCat(t, eventStore, "cat-1")
+	for _, householdID := range []string{"household-a", "household-b"} {
+		if created := handle(t, service, householdID, core.CreateHousehold{CommandID: "create-" + householdID, Name: "Home", OwnerRef: "person-ana"}); !created.Ok {
+			t.Fatalf("create %s: %v", householdID, created.Rejection)
+		}
+	}
+
+	var winner Result
+	eventStore.competitor = func() {
+		winner = handle(t, service, "household-a", core.AddCatToHousehold{CommandID: "add-winner", CatID: "cat-1"})
+	}
+	loser := handle(t, service, "household-a", core.AddCatToHousehold{CommandID: "add-loser", CatID: "cat-1"})
+	if !winner.Ok || loser.Ok {
+		t.Fatalf("expected only the winner to add the cat, got %+v and %+v", winner, loser)
+	}
+
+	other := handle(t, service, "household-b", core.AddCatToHousehold{CommandID: "add-other", CatID: "cat-1"})
+	if other.Rejection == nil || other.Rejection.Code != core.CodeCatInAnotherHousehold {
+		t.Fatalf("expected %q for household-b, got %+v", core.CodeCatInAnotherHousehold, other)
+	}
+}
+
+func TestHandleCommandGivenPaddedCatIDWhenAddThenResolvesTheTrimmedCat(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore)
+	seedCat(t, eventStore, "cat-1")
+	if created := handle(t, service, "household-a", core.CreateHousehold{CommandID: "create", Name: "Home", OwnerRef: "person-ana"}); !created.Ok {
+		t.Fatalf("create: %v", created.Rejection)
+	}
+
+	if added := handle(t, service, "household-a", core.AddCatToHousehold{CommandID: "add", CatID: " cat-1 "}); !added.Ok {
+		t.Fatalf("add padded cat id: %v", added.Rejection)
+	}
+}
+
+func TestHandleCommandGivenNilCommandWhenHandleThenRejectsInvalidCommand(t *testing.T) {
+	service := NewService(store.NewInMemoryStore())
+
+	result := handle(t, service, "household-1", nil)
+	if result.Ok || result.Rejection == nil || result.Rejection.Code != core.CodeInvalidCommand {
+		t.Fatalf("expected %q rejection, got %+v", core.CodeInvalidCommand, result)
+	}
+}
+
+func TestHandleCommandGivenNoActorWhenHandleThenFailsWithoutAppending(t *testing.T) {
+	eventStore := store.NewInMemoryStore()
+	service := NewService(eventStore)
+
+	_, err := service.HandleCommand(context.Background(), CommandEnvelope{
+		AggregateID: "household-1",
+		Command:     core.CreateHousehold{CommandID: "create", Name: "Home"},
+	})
+	if err == nil {
+		t.Fatalf("expected a missing actor type to fail")
+	}
+	if _, version, _ := eventStore.Load(context.Background(), "household-1"); version != 0 {
+		t.Fatalf("version = %d, want nothing appended", version)
+	}
+}